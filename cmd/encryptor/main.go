@@ -11,17 +11,18 @@ import (
 
 func main() {
 
-	key := []byte(os.Getenv("ENCRYPTOR_KEY"))
-	enc, err := encryptor.NewAes256Encryption(key)
+	keyring, err := loadKeyring()
 	if err != nil {
-		log.Fatal("failed to create encryption method ", len(key), err)
+		log.Fatal("failed to load encryption keys ", err)
 	}
 
+	enc := encryptor.NewAes256GCMEncryption(keyring)
+
 	handler := encryptor.NewServer(enc)
 
 	srv := http.Server{
 		Addr:         ":8080",
-		Handler:      handler.HandleEncrypt(),
+		Handler:      handler.Router(),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
@@ -30,3 +31,30 @@ func main() {
 
 	log.Fatal(srv.ListenAndServe())
 }
+
+// loadKeyring reads ENCRYPTOR_KEYS ("id1:key1,id2:key2") together with
+// ENCRYPTOR_ACTIVE_KEY_ID and ENCRYPTOR_LEGACY_KEY_ID. When ENCRYPTOR_KEYS is
+// not set the single ENCRYPTOR_KEY is used as both active and legacy key.
+func loadKeyring() (*encryptor.Keyring, error) {
+	if os.Getenv("ENCRYPTOR_KEYS") == "" {
+		keyID := os.Getenv("ENCRYPTOR_KEY_ID")
+		if keyID == "" {
+			keyID = "default"
+		}
+
+		return encryptor.NewKeyring(keyID, keyID, map[string][]byte{
+			keyID: []byte(os.Getenv("ENCRYPTOR_KEY")),
+		})
+	}
+
+	keys, err := encryptor.ParseKeys(os.Getenv("ENCRYPTOR_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptor.NewKeyring(
+		os.Getenv("ENCRYPTOR_ACTIVE_KEY_ID"),
+		os.Getenv("ENCRYPTOR_LEGACY_KEY_ID"),
+		keys,
+	)
+}
@@ -4,6 +4,8 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"stockplay/internal/apps/encryptor/pkg/client"
@@ -18,19 +20,57 @@ func main() {
 		Timeout: 10 * time.Second,
 	}
 
+	// csv unless set to json
+	dataType, err := alphavantage.ParseDataType(os.Getenv("ALPHAVANTAGE_DATATYPE"))
+	if err != nil {
+		log.Fatal("invalid ALPHAVANTAGE_DATATYPE ", err)
+	}
+
 	encClient := client.NewClient(httpClient, os.Getenv("ENCRYPTOR_HOST"))
 	alphaVantageClient := alphavantage.NewClient(
 		httpClient,
 		os.Getenv("ALPHAVANTAGE_HOST"),
 		os.Getenv("ALPHAVANTAGE_KEY"),
+		// requests can't outlive the write timeout, so waiting for room
+		// would only turn a clear 429 into a dropped connection
+		alphavantage.WithRateLimit(alphavantage.RateLimit{
+			PerMinute: envInt("ALPHAVANTAGE_CALLS_PER_MINUTE", 5),
+			PerDay:    envInt("ALPHAVANTAGE_CALLS_PER_DAY", 25),
+			FailFast:  true,
+		}),
+		alphavantage.WithDataType(dataType),
 	)
-	stockGetter := stockgetter.NewAlphaVantageStockGetter(alphaVantageClient)
 
-	handler := stocks.NewServer(stockGetter, encClient)
+	var getterOpts []stockgetter.Option
+	if dir := os.Getenv("STOCKS_DATA_DIR"); dir != "" {
+		store, err := stockgetter.NewFileStore(dir)
+		if err != nil {
+			log.Fatal("failed to open series store ", err)
+		}
+
+		getterOpts = append(getterOpts, stockgetter.WithStore(store))
+	}
+
+	stockGetter := stockgetter.NewAlphaVantageStockGetter(alphaVantageClient, getterOpts...)
+
+	cachedStockGetter := stockgetter.NewCachedStockGetter(stockGetter, stockgetter.DefaultCacheConfig())
+
+	handler := stocks.NewServer(
+		cachedStockGetter,
+		encClient,
+		stocks.WithQuoteGetter(stockGetter),
+		stocks.WithFundamentalsGetter(stockGetter),
+		stocks.WithIndicatorGetter(stockGetter),
+		stocks.WithSymbolSearcher(stockGetter),
+		stocks.WithBudgetReporter(alphaVantageClient),
+		stocks.WithContentPolicy(stocks.ContentPolicy{
+			PlaintextTokens: envList("STOCKS_PLAINTEXT_TOKENS"),
+		}),
+	)
 
 	srv := http.Server{
 		Addr:         ":8080",
-		Handler:      handler.HandleGetStock(),
+		Handler:      handler.Router(),
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 10 * time.Second,
 	}
@@ -39,3 +79,27 @@ func main() {
 
 	log.Fatal(srv.ListenAndServe())
 }
+
+// envInt reads an integer environment variable, falling back to def when it is
+// not set or not a number.
+func envInt(name string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(name))
+	if err != nil {
+		return def
+	}
+
+	return v
+}
+
+// envList reads a comma separated environment variable, empty items are
+// dropped.
+func envList(name string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
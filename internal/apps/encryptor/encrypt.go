@@ -16,7 +16,10 @@ type Aes256Encryption struct {
 }
 
 var (
-	ErrInvalidKey = errors.New("key must be 32 bytes of length")
+	ErrInvalidKey        = errors.New("key must be 32 bytes of length")
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
+	errUnpad = errors.New("unpad error, probably wrong encryption key")
 )
 
 func NewAes256Encryption(key []byte) (*Aes256Encryption, error) {
@@ -61,6 +64,12 @@ func (a *Aes256Encryption) Decrypt(text string) (string, error) {
 		return "", errors.New("blocksize must be multipe of decoded message length")
 	}
 
+	// padding always adds at least one byte, so a valid ciphertext is the iv
+	// followed by at least one full block
+	if len(decodedMsg) < 2*aes.BlockSize {
+		return "", ErrInvalidCiphertext
+	}
+
 	iv := decodedMsg[:aes.BlockSize]
 	msg := decodedMsg[aes.BlockSize:]
 
@@ -81,12 +90,20 @@ func pad(src []byte) []byte {
 	return append(src, padtext...)
 }
 
+// unpad checks every padding byte, as the ciphertext has no authentication it
+// is the only sign left of a wrong key or a tampered payload.
 func unpad(src []byte) ([]byte, error) {
 	length := len(src)
 	unpadding := int(src[length-1])
 
-	if unpadding > length {
-		return nil, errors.New("unpad error, probably wrong encryption key")
+	if unpadding == 0 || unpadding > aes.BlockSize || unpadding > length {
+		return nil, errUnpad
+	}
+
+	for _, b := range src[length-unpadding:] {
+		if int(b) != unpadding {
+			return nil, errUnpad
+		}
 	}
 
 	return src[:(length - unpadding)], nil
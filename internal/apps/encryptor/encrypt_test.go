@@ -1,6 +1,8 @@
 package encryptor
 
 import (
+	"crypto/aes"
+	"encoding/base64"
 	"testing"
 )
 
@@ -54,6 +56,19 @@ func TestEncDec(t *testing.T) {
 	}
 }
 
+func TestDecryptShortCiphertext(t *testing.T) {
+	encryptor, err := NewAes256Encryption([]byte("abcdefghijklmnopqrstuvwxyz012345"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a single block holds only the iv, there is nothing left to decrypt
+	_, err = encryptor.Decrypt(base64.URLEncoding.EncodeToString(make([]byte, aes.BlockSize)))
+	if err != ErrInvalidCiphertext {
+		t.Error("expected err:", ErrInvalidCiphertext, ", is not err:", err)
+	}
+}
+
 func checkErr(t *testing.T, expectedErr error, err error) bool {
 	if err != nil {
 		if expectedErr == err {
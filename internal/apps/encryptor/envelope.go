@@ -0,0 +1,72 @@
+package encryptor
+
+import (
+	"errors"
+)
+
+const (
+	envelopeVersion1 byte = 1
+
+	// AlgorithmAes256GCM identifies payloads sealed with AES-256-GCM
+	AlgorithmAes256GCM byte = 1
+
+	maxKeyIDLength = 255
+)
+
+var (
+	ErrInvalidKeyID    = errors.New("key id must be between 1 and 255 bytes of length")
+	errInvalidEnvelope = errors.New("invalid envelope")
+)
+
+// envelope is the self describing ciphertext layout:
+//
+//	version (1 byte) | algorithm (1 byte) | key id length (1 byte) | key id | nonce | ciphertext+tag
+//
+// the header (everything before the nonce) is authenticated as additional
+// data, so changing the algorithm or key id fails decryption too. A changed
+// version, or an algorithm or key id the keyring doesn't have, is left to the
+// legacy padding check, as legacy payloads can start like an envelope.
+type envelope struct {
+	Version    byte
+	Algorithm  byte
+	KeyID      string
+	Nonce      []byte
+	Ciphertext []byte
+}
+
+func (e envelope) header() []byte {
+	header := make([]byte, 0, 3+len(e.KeyID))
+	header = append(header, e.Version, e.Algorithm, byte(len(e.KeyID)))
+	return append(header, e.KeyID...)
+}
+
+func (e envelope) marshal() []byte {
+	header := e.header()
+
+	out := make([]byte, 0, len(header)+len(e.Nonce)+len(e.Ciphertext))
+	out = append(out, header...)
+	out = append(out, e.Nonce...)
+	return append(out, e.Ciphertext...)
+}
+
+// parseEnvelope splits raw into its envelope fields. nonceSize and overhead
+// come from the algorithm, a payload too short to hold them is rejected.
+func parseEnvelope(raw []byte, nonceSize, overhead int) (envelope, error) {
+	if len(raw) < 3 {
+		return envelope{}, errInvalidEnvelope
+	}
+
+	keyIDLen := int(raw[2])
+	if keyIDLen == 0 || len(raw) < 3+keyIDLen+nonceSize+overhead {
+		return envelope{}, errInvalidEnvelope
+	}
+
+	nonceStart := 3 + keyIDLen
+	return envelope{
+		Version:    raw[0],
+		Algorithm:  raw[1],
+		KeyID:      string(raw[3:nonceStart]),
+		Nonce:      raw[nonceStart : nonceStart+nonceSize],
+		Ciphertext: raw[nonceStart+nonceSize:],
+	}, nil
+}
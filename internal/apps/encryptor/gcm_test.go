@@ -0,0 +1,236 @@
+package encryptor
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+const (
+	testKey      = "abcdefghijklmnopqrstuvwxyz012345"
+	testOtherKey = "543210zyxwvutsrqponmlkjihgfedcba"
+)
+
+func newTestGCM(t *testing.T, activeID, legacyID string) *Aes256GCMEncryption {
+	keyring, err := NewKeyring(activeID, legacyID, map[string][]byte{
+		"k1": []byte(testKey),
+		"k2": []byte(testOtherKey),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewAes256GCMEncryption(keyring)
+}
+
+func TestGCMEncDec(t *testing.T) {
+	var tts = []struct {
+		caseName  string
+		plainText string
+	}{
+		{
+			caseName:  "successfully encrypt then decrypt it",
+			plainText: "valid plain text",
+		},
+		{
+			caseName:  "successfully encrypt then decrypt empty text",
+			plainText: "",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Log(tt.caseName)
+
+		encryptor := newTestGCM(t, "k1", "")
+
+		encrypted, err := encryptor.Encrypt(tt.plainText)
+		if !checkErr(t, nil, err) {
+			continue
+		}
+
+		decrypted, err := encryptor.Decrypt(encrypted)
+		if !checkErr(t, nil, err) {
+			continue
+		}
+
+		if decrypted != tt.plainText {
+			t.Error(
+				"plaintext is not the same compared to decrypted text",
+				"decrypted", decrypted,
+				"plain text", tt.plainText,
+			)
+		}
+	}
+}
+
+func TestGCMDecryptAfterRotation(t *testing.T) {
+	before := newTestGCM(t, "k1", "")
+	after := newTestGCM(t, "k2", "")
+
+	encrypted, err := before.Encrypt("sealed before rotation")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted, err := after.Decrypt(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decrypted != "sealed before rotation" {
+		t.Error("expected text sealed before rotation, got", decrypted)
+	}
+}
+
+func TestGCMDecryptLegacyPayload(t *testing.T) {
+	legacy, err := NewAes256Encryption([]byte(testKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var encrypted string
+	for {
+		encrypted, err = legacy.Encrypt("legacy plain text")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if raw, _ := base64.URLEncoding.DecodeString(encrypted); raw[0] != envelopeVersion1 {
+			break
+		}
+	}
+
+	decrypted, err := newTestGCM(t, "k2", "k1").Decrypt(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decrypted != "legacy plain text" {
+		t.Error("expected decrypted legacy text, got", decrypted)
+	}
+
+	_, err = newTestGCM(t, "k2", "").Decrypt(encrypted)
+	if err != ErrUnknownKey {
+		t.Error("expected err:", ErrUnknownKey, ", is not err:", err)
+	}
+}
+
+func TestGCMDecryptLegacyPayloadLikeEnvelope(t *testing.T) {
+	legacy, err := NewAes256Encryption([]byte(testKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// one legacy iv in 256 starts with the envelope version
+	var encrypted string
+	for {
+		encrypted, err = legacy.Encrypt("legacy plain text")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if raw, _ := base64.URLEncoding.DecodeString(encrypted); raw[0] == envelopeVersion1 {
+			break
+		}
+	}
+
+	decrypted, err := newTestGCM(t, "k2", "k1").Decrypt(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decrypted != "legacy plain text" {
+		t.Error("expected decrypted legacy text, got", decrypted)
+	}
+}
+
+func TestGCMDecryptTampered(t *testing.T) {
+	// without a legacy key a tampered header has nothing to fall back on,
+	// TestGCMDecryptTamperedHeader covers the fallback
+	encryptor := newTestGCM(t, "k1", "")
+
+	// 15 bytes make the envelope a multiple of the legacy block size
+	encrypted, err := encryptor.Encrypt("valid plaintext")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := base64.URLEncoding.DecodeString(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tts = []struct {
+		caseName string
+		index    int
+		mask     byte
+	}{
+		{caseName: "tampered ciphertext", index: len(raw) - 1, mask: 0xff},
+		{caseName: "tampered nonce", index: 3 + len("k1"), mask: 0xff},
+		{caseName: "tampered algorithm", index: 1, mask: 0xff},
+		{caseName: "tampered key id length", index: 2, mask: 0x01},
+		{caseName: "tampered key id to an unknown one", index: 3, mask: 0xff},
+		{caseName: "tampered key id to another known one", index: 4, mask: '1' ^ '2'},
+	}
+
+	for _, tt := range tts {
+		t.Log(tt.caseName)
+
+		tampered := append([]byte(nil), raw...)
+		tampered[tt.index] ^= tt.mask
+
+		_, err := encryptor.Decrypt(base64.URLEncoding.EncodeToString(tampered))
+		if err != ErrAuthenticationFailed {
+			t.Error("expected err:", ErrAuthenticationFailed, ", is not err:", err)
+		}
+	}
+}
+
+func TestGCMDecryptTamperedHeader(t *testing.T) {
+	encryptor := newTestGCM(t, "k1", "k2")
+	withoutLegacy := newTestGCM(t, "k1", "")
+
+	var tts = []struct {
+		caseName    string
+		index       int
+		expectedErr error
+	}{
+		{caseName: "tampered version", index: 0, expectedErr: ErrUnknownKey},
+		{caseName: "tampered algorithm", index: 1, expectedErr: ErrAuthenticationFailed},
+		{caseName: "tampered key id", index: 3, expectedErr: ErrAuthenticationFailed},
+	}
+
+	for _, tt := range tts {
+		t.Log(tt.caseName)
+
+		// a header that names nothing this keyring has reads as legacy,
+		// which refuses what its padding check catches. The check is all a
+		// legacy payload has, so the runs that slip through are bounded
+		// rather than zero.
+		var opened int
+		for i := 0; i < 2000; i++ {
+			encrypted, err := encryptor.Encrypt("valid plaintext")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			raw, err := base64.URLEncoding.DecodeString(encrypted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw[tt.index] ^= 0xff
+			tampered := base64.URLEncoding.EncodeToString(raw)
+
+			if _, err := encryptor.Decrypt(tampered); err == nil {
+				opened++
+			}
+
+			if _, err := withoutLegacy.Decrypt(tampered); err != tt.expectedErr {
+				t.Fatal("expected err:", tt.expectedErr, ", is not err:", err)
+			}
+		}
+
+		if opened > 40 {
+			t.Error("expected at most 40 of 2000 tampered headers to open, opened:", opened)
+		}
+	}
+}
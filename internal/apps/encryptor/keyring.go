@@ -0,0 +1,99 @@
+package encryptor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrUnknownKey      = errors.New("unknown key id")
+	ErrInvalidKeySpec  = errors.New("key spec must be a comma separated list of id:key pairs")
+	ErrDuplicateKeyID  = errors.New("duplicate key id")
+	ErrMissingActiveID = errors.New("active key id is not in the keyring")
+)
+
+// Keyring holds every key the service can decrypt with. New messages are
+// sealed with the active key, payloads from before envelopes existed are
+// opened with the legacy key.
+type Keyring struct {
+	activeID string
+	legacyID string
+	keys     map[string][]byte
+}
+
+// NewKeyring validates keys and returns a keyring sealing with activeID.
+// legacyID may be empty when no pre-envelope payloads need decrypting.
+func NewKeyring(activeID, legacyID string, keys map[string][]byte) (*Keyring, error) {
+	copied := make(map[string][]byte, len(keys))
+	for id, key := range keys {
+		if len(id) == 0 || len(id) > maxKeyIDLength {
+			return nil, ErrInvalidKeyID
+		}
+
+		if len(key) != 32 {
+			return nil, fmt.Errorf("key %s: %w", id, ErrInvalidKey)
+		}
+
+		copied[id] = key
+	}
+
+	if _, ok := copied[activeID]; !ok {
+		return nil, ErrMissingActiveID
+	}
+
+	if _, ok := copied[legacyID]; legacyID != "" && !ok {
+		return nil, fmt.Errorf("legacy key %s: %w", legacyID, ErrUnknownKey)
+	}
+
+	return &Keyring{activeID: activeID, legacyID: legacyID, keys: copied}, nil
+}
+
+// ParseKeys reads keys in the form "id1:key1,id2:key2".
+func ParseKeys(spec string) (map[string][]byte, error) {
+	keys := map[string][]byte{}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		sep := strings.Index(pair, ":")
+		if sep <= 0 {
+			return nil, ErrInvalidKeySpec
+		}
+
+		id := pair[:sep]
+		if _, ok := keys[id]; ok {
+			return nil, fmt.Errorf("key %s: %w", id, ErrDuplicateKeyID)
+		}
+
+		keys[id] = []byte(pair[sep+1:])
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrInvalidKeySpec
+	}
+
+	return keys, nil
+}
+
+// Active returns the id and key used to seal new messages.
+func (k *Keyring) Active() (string, []byte) {
+	return k.activeID, k.keys[k.activeID]
+}
+
+// Key returns the key registered under id.
+func (k *Keyring) Key(id string) ([]byte, bool) {
+	key, ok := k.keys[id]
+	return key, ok
+}
+
+// Legacy returns the key for payloads sealed before envelopes existed.
+func (k *Keyring) Legacy() ([]byte, bool) {
+	if k.legacyID == "" {
+		return nil, false
+	}
+
+	return k.keys[k.legacyID], true
+}
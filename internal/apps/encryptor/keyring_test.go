@@ -0,0 +1,112 @@
+package encryptor
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestNewKeyring(t *testing.T) {
+	var tts = []struct {
+		caseName string
+		activeID string
+		legacyID string
+		keys     map[string][]byte
+		err      error
+	}{
+		{
+			caseName: "key length is not 32",
+			activeID: "k1",
+			keys:     map[string][]byte{"k1": []byte("abc123")},
+			err:      ErrInvalidKey,
+		},
+		{
+			caseName: "active key is missing",
+			activeID: "k2",
+			keys:     map[string][]byte{"k1": []byte(testKey)},
+			err:      ErrMissingActiveID,
+		},
+		{
+			caseName: "legacy key is missing",
+			activeID: "k1",
+			legacyID: "k2",
+			keys:     map[string][]byte{"k1": []byte(testKey)},
+			err:      ErrUnknownKey,
+		},
+		{
+			caseName: "valid keyring",
+			activeID: "k2",
+			legacyID: "k1",
+			keys:     map[string][]byte{"k1": []byte(testKey), "k2": []byte(testOtherKey)},
+			err:      nil,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		keyring, err := NewKeyring(tt.activeID, tt.legacyID, tt.keys)
+		if !errors.Is(err, tt.err) {
+			t.Error(logTestcase, "expected err:", tt.err, ", is not err:", err)
+			continue
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if id, _ := keyring.Active(); id != tt.activeID {
+			t.Error(logTestcase, "expected active id:", tt.activeID, ", not equal:", id)
+		}
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		spec         string
+		expectedKeys map[string][]byte
+		err          error
+	}{
+		{
+			caseName: "empty spec",
+			spec:     "",
+			err:      ErrInvalidKeySpec,
+		},
+		{
+			caseName: "missing separator",
+			spec:     "k1" + testKey,
+			err:      ErrInvalidKeySpec,
+		},
+		{
+			caseName: "duplicate id",
+			spec:     "k1:" + testKey + ",k1:" + testOtherKey,
+			err:      ErrDuplicateKeyID,
+		},
+		{
+			caseName: "multiple keys",
+			spec:     "k1:" + testKey + ", k2:" + testOtherKey,
+			expectedKeys: map[string][]byte{
+				"k1": []byte(testKey),
+				"k2": []byte(testOtherKey),
+			},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		keys, err := ParseKeys(tt.spec)
+		if !errors.Is(err, tt.err) {
+			t.Error(logTestcase, "expected err:", tt.err, ", is not err:", err)
+			continue
+		}
+
+		if !reflect.DeepEqual(keys, tt.expectedKeys) {
+			t.Errorf("%s received keys %v not equal expected keys %v", logTestcase, keys, tt.expectedKeys)
+		}
+	}
+}
@@ -7,12 +7,32 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+
+	"stockplay/internal/pkg/apierror"
 )
 
 var (
 	ErrServerError = errors.New("server error")
+	ErrUnavailable = errors.New("encryptor unavailable")
 )
 
+// ResponseError is an error response from the encryptor, it matches
+// ErrServerError with errors.Is. API is set when the encryptor explained the
+// failure with an error body.
+type ResponseError struct {
+	StatusCode int
+	Body       string
+	API        *apierror.Error
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf("error respons from encryptor status code [%d] message [%s]", e.StatusCode, e.Body)
+}
+
+func (e *ResponseError) Unwrap() error {
+	return ErrServerError
+}
+
 type Client struct {
 	httpClient *http.Client
 	host       string
@@ -26,7 +46,20 @@ func NewClient(httpClient *http.Client, host string) *Client {
 }
 
 func (c *Client) Encrypt(ctx context.Context, text []byte) ([]byte, error) {
-	req, err := http.NewRequest(http.MethodPost, c.host, bytes.NewBuffer(text))
+	return c.post(ctx, "/encrypt", text)
+}
+
+func (c *Client) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return c.post(ctx, "/decrypt", ciphertext)
+}
+
+// Rewrap returns ciphertext sealed again under the encryptor's active key.
+func (c *Client) Rewrap(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return c.post(ctx, "/rewrap", ciphertext)
+}
+
+func (c *Client) post(ctx context.Context, path string, payload []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, c.host+path, bytes.NewBuffer(payload))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -34,7 +67,12 @@ func (c *Client) Encrypt(ctx context.Context, text []byte) ([]byte, error) {
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		// the caller's context ending is not the encryptor's fault
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to execute request: %w", ctx.Err())
+		}
+
+		return nil, fmt.Errorf("failed to execute request: %v: %w", err, ErrUnavailable)
 	}
 	defer resp.Body.Close()
 
@@ -44,10 +82,10 @@ func (c *Client) Encrypt(ctx context.Context, text []byte) ([]byte, error) {
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf(
-			"error respons from encryptor status code [%d] message [%s]: %w",
-			resp.StatusCode, string(body), ErrServerError,
-		)
+		respErr := &ResponseError{StatusCode: resp.StatusCode, Body: string(body)}
+		respErr.API, _ = apierror.Parse(resp.StatusCode, body)
+
+		return nil, respErr
 	}
 
 	return body, nil
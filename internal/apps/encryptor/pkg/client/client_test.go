@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"stockplay/internal/pkg/apierror"
 )
 
 func TestEncrypt(t *testing.T) {
@@ -28,6 +30,11 @@ func TestEncrypt(t *testing.T) {
 		{
 			caseName: "when success",
 			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/encrypt" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
 				w.WriteHeader(http.StatusOK)
 				w.Write([]byte("abcd1234"))
 			},
@@ -61,3 +68,118 @@ func TestEncrypt(t *testing.T) {
 		server.Close()
 	}
 }
+
+func TestDecrypt(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		handler      http.HandlerFunc
+		expectedResp []byte
+		expectedErr  error
+	}{
+		{
+			caseName: "when error response from server",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("error"))
+			},
+			expectedResp: nil,
+			expectedErr:  ErrServerError,
+		},
+		{
+			caseName: "when success",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/decrypt" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte("plain text"))
+			},
+			expectedResp: []byte("plain text"),
+			expectedErr:  nil,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		server := httptest.NewServer(tt.handler)
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       server.URL,
+		}
+
+		resp, err := c.Decrypt(context.Background(), []byte("abcd1234"))
+		if err != nil {
+			if !errors.Is(err, tt.expectedErr) {
+				t.Error("expected err:", tt.expectedErr, ", is not err:", err)
+			}
+		}
+
+		if string(resp) != string(tt.expectedResp) {
+			t.Error("expected resp:", string(tt.expectedResp), ", not equal:", string(resp))
+		}
+
+		server.Close()
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rewrap" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("efgh5678"))
+	}))
+	defer server.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       server.URL,
+	}
+
+	resp, err := c.Rewrap(context.Background(), []byte("abcd1234"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(resp) != "efgh5678" {
+		t.Error("expected resp:", "efgh5678", ", not equal:", string(resp))
+	}
+}
+
+func TestEncryptErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeAuthenticationFailed, "ciphertext failed authentication"))
+	}))
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       server.URL,
+	}
+
+	_, err := c.Decrypt(context.Background(), []byte("abcd1234"))
+
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) || respErr.API == nil || respErr.API.Code != apierror.CodeAuthenticationFailed {
+		t.Error("expected error body to be parsed, got", err)
+	}
+
+	if !errors.Is(err, ErrServerError) {
+		t.Error("expected err:", ErrServerError, ", is not err:", err)
+	}
+
+	// nothing listens on the address anymore
+	server.Close()
+
+	_, err = c.Encrypt(context.Background(), []byte("abcd1234"))
+	if !errors.Is(err, ErrUnavailable) {
+		t.Error("expected err:", ErrUnavailable, ", is not err:", err)
+	}
+}
@@ -1,13 +1,17 @@
 package encryptor
 
 import (
+	"errors"
 	"io/ioutil"
 	"log"
 	"net/http"
+
+	"stockplay/internal/pkg/apierror"
 )
 
 type Encryptor interface {
 	Encrypt(text string) (string, error)
+	Decrypt(text string) (string, error)
 }
 
 type Server struct {
@@ -18,14 +22,35 @@ func NewServer(enc Encryptor) *Server {
 	return &Server{enc: enc}
 }
 
+// Router serves encryption at /encrypt, decryption at /decrypt and re-encryption
+// under the active key at /rewrap. The root path still encrypts so clients
+// written against the single route keep working.
+func (s *Server) Router() http.Handler {
+	encrypt := s.HandleEncrypt()
+
+	mux := http.NewServeMux()
+	mux.Handle("/encrypt", encrypt)
+	mux.Handle("/decrypt", s.HandleDecrypt())
+	mux.Handle("/rewrap", s.HandleRewrap())
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			apierror.Write(w, r, apierror.New(http.StatusNotFound, apierror.CodeNotFound, "no such route"))
+			return
+		}
+
+		encrypt.ServeHTTP(w, r)
+	})
+
+	return apierror.WithRequestID(mux)
+}
+
 func (s *Server) HandleEncrypt() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		body, err := ioutil.ReadAll(r.Body)
 		if err != nil {
 			log.Println("failed to read body", err)
 
-			w.WriteHeader(http.StatusBadRequest)
-			w.Write([]byte("failed to read body"))
+			apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeBadRequest, "failed to read body"))
 			return
 		}
 
@@ -33,8 +58,7 @@ func (s *Server) HandleEncrypt() http.HandlerFunc {
 		if err != nil {
 			log.Println("failed to encrypt message", err)
 
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("failed to encrypt message"))
+			apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeEncryptionFailed, "failed to encrypt message"))
 			return
 		}
 
@@ -42,3 +66,72 @@ func (s *Server) HandleEncrypt() http.HandlerFunc {
 		w.Write([]byte(encrypted))
 	}
 }
+
+func (s *Server) HandleDecrypt() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			log.Println("failed to read body", err)
+
+			apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeBadRequest, "failed to read body"))
+			return
+		}
+
+		decrypted, err := s.enc.Decrypt(string(body))
+		if err != nil {
+			log.Println("failed to decrypt message", err)
+
+			apierror.Write(w, r, decryptError(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(decrypted))
+	}
+}
+
+// HandleRewrap takes a ciphertext sealed under any known key and returns it
+// sealed under the active key.
+func (s *Server) HandleRewrap() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			log.Println("failed to read body", err)
+
+			apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeBadRequest, "failed to read body"))
+			return
+		}
+
+		decrypted, err := s.enc.Decrypt(string(body))
+		if err != nil {
+			log.Println("failed to decrypt message", err)
+
+			apierror.Write(w, r, decryptError(err))
+			return
+		}
+
+		encrypted, err := s.enc.Encrypt(decrypted)
+		if err != nil {
+			log.Println("failed to encrypt message", err)
+
+			apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeEncryptionFailed, "failed to encrypt message"))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(encrypted))
+	}
+}
+
+// decryptError explains why a ciphertext couldn't be opened. The ciphertext
+// comes from the caller, so it is a bad request rather than a server fault.
+func decryptError(err error) *apierror.Error {
+	switch {
+	case errors.Is(err, ErrAuthenticationFailed):
+		return apierror.New(http.StatusBadRequest, apierror.CodeAuthenticationFailed, "ciphertext failed authentication")
+	case errors.Is(err, ErrUnknownKey):
+		return apierror.New(http.StatusBadRequest, apierror.CodeUnknownKey, "ciphertext was sealed with an unknown key")
+	}
+
+	return apierror.New(http.StatusBadRequest, apierror.CodeInvalidCiphertext, "failed to decrypt message")
+}
@@ -7,6 +7,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"stockplay/internal/pkg/apierror"
 )
 
 type successEnc int
@@ -15,12 +17,32 @@ func (s successEnc) Encrypt(text string) (string, error) {
 	return "abcd1234", nil
 }
 
+func (s successEnc) Decrypt(text string) (string, error) {
+	return "plain text", nil
+}
+
+type errEnc struct {
+	err error
+}
+
+func (e errEnc) Encrypt(text string) (string, error) {
+	return "", e.err
+}
+
+func (e errEnc) Decrypt(text string) (string, error) {
+	return "", e.err
+}
+
 type failEnc int
 
 func (f failEnc) Encrypt(text string) (string, error) {
 	return "", errors.New("any error")
 }
 
+func (f failEnc) Decrypt(text string) (string, error) {
+	return "", errors.New("any error")
+}
+
 func TestEncryptionHandler(t *testing.T) {
 	var tts = []struct {
 		caseName           string
@@ -33,7 +55,7 @@ func TestEncryptionHandler(t *testing.T) {
 			caseName:           "when failed to encrypt request body",
 			enc:                failEnc(1),
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedBody:       "failed to encrypt message",
+			expectedBody:       `{"code":"encryption_failed","message":"failed to encrypt message","retryable":false}` + "\n",
 			requestBody:        []byte("any body"),
 		},
 		{
@@ -69,3 +91,153 @@ func TestEncryptionHandler(t *testing.T) {
 		}
 	}
 }
+
+func TestDecryptionHandler(t *testing.T) {
+	var tts = []struct {
+		caseName           string
+		enc                Encryptor
+		expectedStatusCode int
+		expectedBody       string
+		requestBody        []byte
+	}{
+		{
+			caseName:           "when failed to decrypt request body",
+			enc:                failEnc(1),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_ciphertext","message":"failed to decrypt message","retryable":false}` + "\n",
+			requestBody:        []byte("any body"),
+		},
+		{
+			caseName:           "when ciphertext was tampered with",
+			enc:                errEnc{err: ErrAuthenticationFailed},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"authentication_failed","message":"ciphertext failed authentication","retryable":false}` + "\n",
+			requestBody:        []byte("any body"),
+		},
+		{
+			caseName:           "when key is unknown",
+			enc:                errEnc{err: ErrUnknownKey},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"unknown_key","message":"ciphertext was sealed with an unknown key","retryable":false}` + "\n",
+			requestBody:        []byte("any body"),
+		},
+		{
+			caseName:           "success case",
+			enc:                successEnc(1),
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "plain text",
+			requestBody:        []byte("abcd1234"),
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		s := Server{enc: tt.enc}
+
+		req, err := http.NewRequest(http.MethodPost, "/decrypt", bytes.NewBuffer(tt.requestBody))
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+
+		rw := httptest.NewRecorder()
+
+		s.HandleDecrypt().ServeHTTP(rw, req)
+
+		if rw.Result().StatusCode != tt.expectedStatusCode {
+			t.Errorf("%s expected status code [%d] not equal to received status code [%d]", logTestcase, tt.expectedStatusCode, rw.Result().StatusCode)
+		}
+
+		if rw.Body.String() != tt.expectedBody {
+			t.Errorf("%s expected body [%s] not equal to received body [%s]", logTestcase, tt.expectedBody, rw.Body.String())
+		}
+	}
+}
+
+func TestRouter(t *testing.T) {
+	var tts = []struct {
+		caseName           string
+		path               string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			caseName:           "encrypt path",
+			path:               "/encrypt",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd1234",
+		},
+		{
+			caseName:           "decrypt path",
+			path:               "/decrypt",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "plain text",
+		},
+		{
+			caseName:           "rewrap path",
+			path:               "/rewrap",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd1234",
+		},
+		{
+			caseName:           "root path still encrypts",
+			path:               "/",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd1234",
+		},
+		{
+			caseName:           "unknown path",
+			path:               "/unknown",
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"code":"not_found","message":"no such route","request_id":"abcd","retryable":false}` + "\n",
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		s := Server{enc: successEnc(1)}
+
+		req, err := http.NewRequest(http.MethodPost, tt.path, bytes.NewBuffer([]byte("any body")))
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+		req.Header.Set(apierror.RequestIDHeader, "abcd")
+
+		rw := httptest.NewRecorder()
+
+		s.Router().ServeHTTP(rw, req)
+
+		if rw.Result().StatusCode != tt.expectedStatusCode {
+			t.Errorf("%s expected status code [%d] not equal to received status code [%d]", logTestcase, tt.expectedStatusCode, rw.Result().StatusCode)
+		}
+
+		if rw.Body.String() != tt.expectedBody {
+			t.Errorf("%s expected body [%s] not equal to received body [%s]", logTestcase, tt.expectedBody, rw.Body.String())
+		}
+	}
+}
+
+func TestRewrapHandler(t *testing.T) {
+	s := Server{enc: failEnc(1)}
+
+	req, err := http.NewRequest(http.MethodPost, "/rewrap", bytes.NewBuffer([]byte("any body")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rw := httptest.NewRecorder()
+
+	s.HandleRewrap().ServeHTTP(rw, req)
+
+	if rw.Result().StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status code [%d] not equal to received status code [%d]", http.StatusBadRequest, rw.Result().StatusCode)
+	}
+
+	expectedBody := `{"code":"invalid_ciphertext","message":"failed to decrypt message","retryable":false}` + "\n"
+	if rw.Body.String() != expectedBody {
+		t.Errorf("expected body [%s] not equal to received body [%s]", expectedBody, rw.Body.String())
+	}
+}
@@ -0,0 +1,365 @@
+// Package indicators computes technical indicators over stockgetter series.
+package indicators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"stockplay/internal/apps/stocks/pkg/stockgetter"
+)
+
+const (
+	maxSpecs  = 10
+	maxPeriod = 1000
+)
+
+var (
+	ErrUnknownIndicator  = errors.New("unknown indicator")
+	ErrInvalidParams     = errors.New("invalid indicator parameters")
+	ErrTooManyIndicators = fmt.Errorf("at most %d indicators per request", maxSpecs)
+)
+
+// Value is one point of an indicator line, points before the indicator has
+// enough history are left out.
+type Value struct {
+	Time  int64   `json:"time"`
+	Value float64 `json:"value"`
+}
+
+// Spec is a requested indicator with its parameters, e.g. sma:20 or
+// macd:12:26:9.
+type Spec struct {
+	Name   string
+	Params []float64
+}
+
+type indicator struct {
+	defaults []float64
+	// integer marks the parameters that have to be whole periods
+	integer []bool
+	compute func(points []stockgetter.Point, params []float64) map[string][]Value
+}
+
+var known = map[string]indicator{
+	"sma":    {defaults: []float64{20}, integer: []bool{true}, compute: sma},
+	"ema":    {defaults: []float64{20}, integer: []bool{true}, compute: ema},
+	"rsi":    {defaults: []float64{14}, integer: []bool{true}, compute: rsi},
+	"macd":   {defaults: []float64{12, 26, 9}, integer: []bool{true, true, true}, compute: macd},
+	"bbands": {defaults: []float64{20, 2}, integer: []bool{true, false}, compute: bbands},
+	"atr":    {defaults: []float64{14}, integer: []bool{true}, compute: atr},
+	"vwap":   {compute: vwap},
+}
+
+// Names lists the supported indicators.
+func Names() []string {
+	return []string{"sma", "ema", "rsi", "macd", "bbands", "atr", "vwap"}
+}
+
+// ParseSpecs reads a comma separated list of indicators, each one optionally
+// followed by colon separated parameters. Missing parameters take the
+// indicator's defaults.
+func ParseSpecs(s string) ([]Spec, error) {
+	var specs []Spec
+	for _, raw := range strings.Split(s, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		parts := strings.Split(raw, ":")
+		name := strings.ToLower(parts[0])
+
+		ind, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", name, ErrUnknownIndicator)
+		}
+
+		if len(parts)-1 > len(ind.defaults) {
+			return nil, fmt.Errorf("%s takes at most %d parameters: %w", name, len(ind.defaults), ErrInvalidParams)
+		}
+
+		params := append([]float64(nil), ind.defaults...)
+		for i, p := range parts[1:] {
+			v, err := strconv.ParseFloat(p, 64)
+			if err != nil || v <= 0 || v > maxPeriod || (ind.integer[i] && v != math.Trunc(v)) {
+				return nil, fmt.Errorf("%s parameter %s: %w", name, p, ErrInvalidParams)
+			}
+
+			params[i] = v
+		}
+
+		specs = append(specs, Spec{Name: name, Params: params})
+	}
+
+	if len(specs) > maxSpecs {
+		return nil, ErrTooManyIndicators
+	}
+
+	return specs, nil
+}
+
+// Compute returns every line of every spec keyed by the indicator name and
+// its parameters, e.g. sma_20 or macd_12_26_9_signal.
+func Compute(points []stockgetter.Point, specs []Spec) map[string][]Value {
+	res := map[string][]Value{}
+	for _, spec := range specs {
+		ind, ok := known[spec.Name]
+		if !ok {
+			continue
+		}
+
+		prefix := spec.Name
+		for _, p := range spec.Params {
+			prefix += "_" + strconv.FormatFloat(p, 'f', -1, 64)
+		}
+
+		for line, values := range ind.compute(points, spec.Params) {
+			name := prefix
+			if line != "" {
+				name += "_" + line
+			}
+
+			res[name] = values
+		}
+	}
+
+	return res
+}
+
+// Window keeps the values of lines that fall within the time of points, so
+// lines computed over a whole series line up with a page of it.
+func Window(lines map[string][]Value, points []stockgetter.Point) map[string][]Value {
+	res := make(map[string][]Value, len(lines))
+	if len(points) == 0 {
+		return res
+	}
+
+	first, last := points[0].Time, points[len(points)-1].Time
+	for name, values := range lines {
+		var kept []Value
+		for _, v := range values {
+			if v.Time >= first && v.Time <= last {
+				kept = append(kept, v)
+			}
+		}
+
+		res[name] = kept
+	}
+
+	return res
+}
+
+func closes(points []stockgetter.Point) []float64 {
+	xs := make([]float64, len(points))
+	for i, p := range points {
+		xs[i] = p.Close
+	}
+
+	return xs
+}
+
+// toValues pairs xs with the point times, skipping the first start values
+// which are still warming up.
+func toValues(points []stockgetter.Point, xs []float64, start int) []Value {
+	if start >= len(xs) {
+		return []Value{}
+	}
+
+	values := make([]Value, 0, len(xs)-start)
+	for i := start; i < len(xs); i++ {
+		values = append(values, Value{Time: points[i].Time, Value: xs[i]})
+	}
+
+	return values
+}
+
+// smaValues is the rolling mean of xs, valid from index n-1.
+func smaValues(xs []float64, n int) []float64 {
+	out := make([]float64, len(xs))
+
+	var sum float64
+	for i, x := range xs {
+		sum += x
+		if i >= n {
+			sum -= xs[i-n]
+		}
+
+		if i >= n-1 {
+			out[i] = sum / float64(n)
+		}
+	}
+
+	return out
+}
+
+// emaValues is the exponential moving average of xs seeded with the simple
+// average of the first n values, valid from index n-1.
+func emaValues(xs []float64, n int) []float64 {
+	out := make([]float64, len(xs))
+	if len(xs) < n {
+		return out
+	}
+
+	k := 2 / float64(n+1)
+	out[n-1] = smaValues(xs[:n], n)[n-1]
+	for i := n; i < len(xs); i++ {
+		out[i] = xs[i]*k + out[i-1]*(1-k)
+	}
+
+	return out
+}
+
+func sma(points []stockgetter.Point, params []float64) map[string][]Value {
+	n := int(params[0])
+
+	return map[string][]Value{"": toValues(points, smaValues(closes(points), n), n-1)}
+}
+
+func ema(points []stockgetter.Point, params []float64) map[string][]Value {
+	n := int(params[0])
+
+	return map[string][]Value{"": toValues(points, emaValues(closes(points), n), n-1)}
+}
+
+// rsi uses Wilder's smoothing, the first value needs n price changes.
+func rsi(points []stockgetter.Point, params []float64) map[string][]Value {
+	n := int(params[0])
+	xs := closes(points)
+	out := make([]float64, len(xs))
+
+	var avgGain, avgLoss float64
+	for i := 1; i < len(xs); i++ {
+		change := xs[i] - xs[i-1]
+		gain, loss := math.Max(change, 0), math.Max(-change, 0)
+
+		if i <= n {
+			avgGain += gain / float64(n)
+			avgLoss += loss / float64(n)
+		} else {
+			avgGain = (avgGain*float64(n-1) + gain) / float64(n)
+			avgLoss = (avgLoss*float64(n-1) + loss) / float64(n)
+		}
+
+		if i >= n {
+			if avgLoss == 0 {
+				out[i] = 100
+			} else {
+				out[i] = 100 - 100/(1+avgGain/avgLoss)
+			}
+		}
+	}
+
+	return map[string][]Value{"": toValues(points, out, n)}
+}
+
+func macd(points []stockgetter.Point, params []float64) map[string][]Value {
+	fast, slow, signal := int(params[0]), int(params[1]), int(params[2])
+	if fast > slow {
+		fast, slow = slow, fast
+	}
+
+	xs := closes(points)
+	fastEMA, slowEMA := emaValues(xs, fast), emaValues(xs, slow)
+
+	start := slow - 1
+	if start >= len(xs) {
+		return map[string][]Value{"": {}, "signal": {}, "histogram": {}}
+	}
+
+	line := make([]float64, len(xs))
+	for i := start; i < len(xs); i++ {
+		line[i] = fastEMA[i] - slowEMA[i]
+	}
+
+	signalLine := make([]float64, len(xs))
+	copy(signalLine[start:], emaValues(line[start:], signal))
+
+	histogram := make([]float64, len(xs))
+	for i := range xs {
+		histogram[i] = line[i] - signalLine[i]
+	}
+
+	signalStart := start + signal - 1
+
+	return map[string][]Value{
+		"":          toValues(points, line, start),
+		"signal":    toValues(points, signalLine, signalStart),
+		"histogram": toValues(points, histogram, signalStart),
+	}
+}
+
+// bbands are k population standard deviations around the n period sma.
+func bbands(points []stockgetter.Point, params []float64) map[string][]Value {
+	n, k := int(params[0]), params[1]
+	xs := closes(points)
+	middle := smaValues(xs, n)
+
+	upper := make([]float64, len(xs))
+	lower := make([]float64, len(xs))
+	for i := n - 1; i < len(xs); i++ {
+		var variance float64
+		for _, x := range xs[i-n+1 : i+1] {
+			variance += (x - middle[i]) * (x - middle[i])
+		}
+
+		dev := k * math.Sqrt(variance/float64(n))
+		upper[i] = middle[i] + dev
+		lower[i] = middle[i] - dev
+	}
+
+	return map[string][]Value{
+		"upper":  toValues(points, upper, n-1),
+		"middle": toValues(points, middle, n-1),
+		"lower":  toValues(points, lower, n-1),
+	}
+}
+
+// atr uses Wilder's smoothing over the true range, the first bar has no
+// previous close so its range is high minus low.
+func atr(points []stockgetter.Point, params []float64) map[string][]Value {
+	n := int(params[0])
+	out := make([]float64, len(points))
+
+	var avg float64
+	for i, p := range points {
+		tr := p.High - p.Low
+		if i > 0 {
+			prevClose := points[i-1].Close
+			tr = math.Max(tr, math.Max(math.Abs(p.High-prevClose), math.Abs(p.Low-prevClose)))
+		}
+
+		if i < n {
+			avg += tr / float64(n)
+		} else {
+			avg = (avg*float64(n-1) + tr) / float64(n)
+		}
+
+		out[i] = avg
+	}
+
+	return map[string][]Value{"": toValues(points, out, n-1)}
+}
+
+// vwap is cumulative over the whole returned series, it is not reset per
+// trading session.
+func vwap(points []stockgetter.Point, params []float64) map[string][]Value {
+	values := make([]Value, 0, len(points))
+
+	var priceVolume, volume float64
+	for _, p := range points {
+		typical := (p.High + p.Low + p.Close) / 3
+		priceVolume += typical * float64(p.Volume)
+		volume += float64(p.Volume)
+
+		if volume == 0 {
+			continue
+		}
+
+		values = append(values, Value{Time: p.Time, Value: priceVolume / volume})
+	}
+
+	return map[string][]Value{"": values}
+}
@@ -0,0 +1,202 @@
+package indicators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"stockplay/internal/apps/stocks/pkg/stockgetter"
+)
+
+func closePoints(closes ...float64) []stockgetter.Point {
+	points := make([]stockgetter.Point, len(closes))
+	for i, c := range closes {
+		points[i] = stockgetter.Point{Close: c, Time: int64(i + 1)}
+	}
+
+	return points
+}
+
+// barPoints takes high, low, close and volume for each bar.
+func barPoints(bars ...[4]float64) []stockgetter.Point {
+	points := make([]stockgetter.Point, len(bars))
+	for i, b := range bars {
+		points[i] = stockgetter.Point{High: b[0], Low: b[1], Close: b[2], Volume: int64(b[3]), Time: int64(i + 1)}
+	}
+
+	return points
+}
+
+func TestParseSpecs(t *testing.T) {
+	var tts = []struct {
+		caseName      string
+		spec          string
+		expectedSpecs []Spec
+		expectedErr   error
+	}{
+		{
+			caseName:      "empty",
+			spec:          "",
+			expectedSpecs: nil,
+		},
+		{
+			caseName: "params and defaults",
+			spec:     "sma:20, RSI,bbands:10:2.5",
+			expectedSpecs: []Spec{
+				{Name: "sma", Params: []float64{20}},
+				{Name: "rsi", Params: []float64{14}},
+				{Name: "bbands", Params: []float64{10, 2.5}},
+			},
+		},
+		{
+			caseName:    "unknown indicator",
+			spec:        "sma:20,foo",
+			expectedErr: ErrUnknownIndicator,
+		},
+		{
+			caseName:    "fractional period",
+			spec:        "sma:2.5",
+			expectedErr: ErrInvalidParams,
+		},
+		{
+			caseName:    "zero period",
+			spec:        "ema:0",
+			expectedErr: ErrInvalidParams,
+		},
+		{
+			caseName:    "too many params",
+			spec:        "rsi:14:2",
+			expectedErr: ErrInvalidParams,
+		},
+		{
+			caseName:    "too many indicators",
+			spec:        "sma,sma,sma,sma,sma,sma,sma,sma,sma,sma,sma",
+			expectedErr: ErrTooManyIndicators,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		specs, err := ParseSpecs(tt.spec)
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if !reflect.DeepEqual(specs, tt.expectedSpecs) {
+			t.Errorf("%s received specs %+v not equal expected %+v", logTestcase, specs, tt.expectedSpecs)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	var tts = []struct {
+		caseName       string
+		points         []stockgetter.Point
+		spec           Spec
+		expectedValues map[string][]Value
+	}{
+		{
+			caseName: "sma",
+			points:   closePoints(1, 2, 3, 4, 5),
+			spec:     Spec{Name: "sma", Params: []float64{3}},
+			expectedValues: map[string][]Value{
+				"sma_3": {{Time: 3, Value: 2}, {Time: 4, Value: 3}, {Time: 5, Value: 4}},
+			},
+		},
+		{
+			caseName: "ema",
+			points:   closePoints(1, 2, 3, 4, 5),
+			spec:     Spec{Name: "ema", Params: []float64{3}},
+			expectedValues: map[string][]Value{
+				"ema_3": {{Time: 3, Value: 2}, {Time: 4, Value: 3}, {Time: 5, Value: 4}},
+			},
+		},
+		{
+			caseName: "rsi",
+			points:   closePoints(1, 2, 3, 2),
+			spec:     Spec{Name: "rsi", Params: []float64{2}},
+			expectedValues: map[string][]Value{
+				"rsi_2": {{Time: 3, Value: 100}, {Time: 4, Value: 50}},
+			},
+		},
+		{
+			caseName: "macd",
+			points:   closePoints(1, 2, 3),
+			spec:     Spec{Name: "macd", Params: []float64{1, 2, 1}},
+			expectedValues: map[string][]Value{
+				"macd_1_2_1":           {{Time: 2, Value: 0.5}, {Time: 3, Value: 0.5}},
+				"macd_1_2_1_signal":    {{Time: 2, Value: 0.5}, {Time: 3, Value: 0.5}},
+				"macd_1_2_1_histogram": {{Time: 2, Value: 0}, {Time: 3, Value: 0}},
+			},
+		},
+		{
+			caseName: "bbands",
+			points:   closePoints(1, 3),
+			spec:     Spec{Name: "bbands", Params: []float64{2, 1}},
+			expectedValues: map[string][]Value{
+				"bbands_2_1_upper":  {{Time: 2, Value: 3}},
+				"bbands_2_1_middle": {{Time: 2, Value: 2}},
+				"bbands_2_1_lower":  {{Time: 2, Value: 1}},
+			},
+		},
+		{
+			caseName: "atr",
+			points:   barPoints([4]float64{3, 1, 2, 0}, [4]float64{4, 2, 3, 0}, [4]float64{6, 3, 5, 0}),
+			spec:     Spec{Name: "atr", Params: []float64{2}},
+			expectedValues: map[string][]Value{
+				"atr_2": {{Time: 2, Value: 2}, {Time: 3, Value: 2.5}},
+			},
+		},
+		{
+			caseName: "vwap",
+			points:   barPoints([4]float64{3, 1, 2, 10}, [4]float64{4, 2, 3, 30}),
+			spec:     Spec{Name: "vwap"},
+			expectedValues: map[string][]Value{
+				"vwap": {{Time: 1, Value: 2}, {Time: 2, Value: 2.75}},
+			},
+		},
+		{
+			caseName: "not enough points",
+			points:   closePoints(1, 2),
+			spec:     Spec{Name: "sma", Params: []float64{3}},
+			expectedValues: map[string][]Value{
+				"sma_3": {},
+			},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		values := Compute(tt.points, []Spec{tt.spec})
+		if len(values) != len(tt.expectedValues) {
+			t.Errorf("%s received lines %+v not equal expected %+v", logTestcase, values, tt.expectedValues)
+			continue
+		}
+
+		for name, expected := range tt.expectedValues {
+			if !almostEqual(values[name], expected) {
+				t.Errorf("%s line %s received %+v not equal expected %+v", logTestcase, name, values[name], expected)
+			}
+		}
+	}
+}
+
+func almostEqual(a, b []Value) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Time != b[i].Time || math.Abs(a[i].Value-b[i].Value) > 1e-9 {
+			return false
+		}
+	}
+
+	return true
+}
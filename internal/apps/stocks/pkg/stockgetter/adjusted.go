@@ -0,0 +1,78 @@
+package stockgetter
+
+import (
+	"stockplay/pkg/alphavantage"
+)
+
+const (
+	CorporateActionDividend = "dividend"
+	CorporateActionSplit    = "split"
+)
+
+// CorporateAction is a dividend or split taking effect at Time. Value is the
+// cash amount per share of a dividend or the new shares per old one of a
+// split, 4 for a 4-for-1.
+type CorporateAction struct {
+	Time  int64   `json:"time"`
+	Type  string  `json:"type"`
+	Value float64 `json:"value"`
+}
+
+// corporateActions lists the dividends and splits of stocks, which must be
+// sorted by date.
+func corporateActions(stocks []alphavantage.Stock) []CorporateAction {
+	var actions []CorporateAction
+	for _, s := range stocks {
+		if s.DividendAmount > 0 {
+			actions = append(actions, CorporateAction{Time: s.Date.Unix(), Type: CorporateActionDividend, Value: s.DividendAmount})
+		}
+
+		if isSplit(s.SplitCoefficient) {
+			actions = append(actions, CorporateAction{Time: s.Date.Unix(), Type: CorporateActionSplit, Value: s.SplitCoefficient})
+		}
+	}
+
+	return actions
+}
+
+// backAdjust rewrites the prices and volumes of stocks, sorted by date, as if
+// every later split and dividend had always been in effect, so that the series
+// has no cliffs where they happened. The latest point is left as it is.
+//
+// The factors are worked out from the actions themselves rather than from the
+// adjusted close alphavantage sends, which changes with every new action and
+// would go stale in the store.
+func backAdjust(stocks []alphavantage.Stock) []alphavantage.Stock {
+	adjusted := make([]alphavantage.Stock, len(stocks))
+
+	priceFactor, volumeFactor := 1.0, 1.0
+	for i := len(stocks) - 1; i >= 0; i-- {
+		s := stocks[i]
+
+		a := s
+		a.Open *= priceFactor
+		a.High *= priceFactor
+		a.Low *= priceFactor
+		a.Close *= priceFactor
+		a.Volume = int64(float64(s.Volume)*volumeFactor + 0.5)
+		adjusted[i] = a
+
+		// actions of this point only move the points before it
+		if isSplit(s.SplitCoefficient) {
+			priceFactor /= s.SplitCoefficient
+			volumeFactor *= s.SplitCoefficient
+		}
+
+		if s.DividendAmount > 0 && i > 0 && stocks[i-1].Close > 0 {
+			priceFactor *= 1 - s.DividendAmount/stocks[i-1].Close
+		}
+	}
+
+	return adjusted
+}
+
+// isSplit tells a split from the coefficient of 1 sent on every other point,
+// and from the 0 of series without corporate actions.
+func isSplit(coefficient float64) bool {
+	return coefficient > 0 && coefficient != 1
+}
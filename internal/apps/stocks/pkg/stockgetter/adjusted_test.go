@@ -0,0 +1,86 @@
+package stockgetter
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+var splitDay = time.Date(2020, 8, 31, 0, 0, 0, 0, time.UTC)
+
+func adjustedSeries() []alphavantage.Stock {
+	return []alphavantage.Stock{
+		{Close: 100, Volume: 10, Date: splitDay.AddDate(0, 0, -2), SplitCoefficient: 1},
+		{Close: 102, Volume: 10, Date: splitDay.AddDate(0, 0, -1), SplitCoefficient: 1},
+		{Close: 50, Volume: 20, Date: splitDay, SplitCoefficient: 2},
+		{Close: 51, Volume: 20, Date: splitDay.AddDate(0, 0, 1), SplitCoefficient: 1, DividendAmount: 1},
+	}
+}
+
+type adjustedAvClient struct {
+	successAvClient
+	mode string
+}
+
+func (a *adjustedAvClient) GetStockTimeSeries(ctx context.Context, args alphavantage.GetStockArgs) ([]alphavantage.Stock, error) {
+	a.mode = args.Mode
+	return adjustedSeries(), nil
+}
+
+func TestBackAdjust(t *testing.T) {
+	adjusted := backAdjust(adjustedSeries())
+
+	expectedCloses := []float64{49, 49.98, 49, 51}
+	expectedVolumes := []int64{20, 20, 20, 20}
+	for i, s := range adjusted {
+		if math.Abs(s.Close-expectedCloses[i]) > 1e-9 {
+			t.Errorf("point %d close [%f] not equal expected [%f]", i, s.Close, expectedCloses[i])
+		}
+
+		if s.Volume != expectedVolumes[i] {
+			t.Errorf("point %d volume [%d] not equal expected [%d]", i, s.Volume, expectedVolumes[i])
+		}
+	}
+}
+
+func TestAlphaVantageStockGetter_GetAdjusted(t *testing.T) {
+	client := &adjustedAvClient{}
+	c := AlphaVantageStockGetter{
+		client: client,
+		now:    func() time.Time { return splitDay },
+	}
+
+	resp, err := c.Get(context.Background(), GetStockArgs{Mode: TimeModeDaily, Symbol: "AAPL", Adjusted: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.mode != alphavantage.ModeTimeSeriesDailyAdjusted {
+		t.Errorf("function [%s] not equal expected [%s]", client.mode, alphavantage.ModeTimeSeriesDailyAdjusted)
+	}
+
+	expectedActions := []CorporateAction{
+		{Time: splitDay.Unix(), Type: CorporateActionSplit, Value: 2},
+		{Time: splitDay.AddDate(0, 0, 1).Unix(), Type: CorporateActionDividend, Value: 1},
+	}
+	if !reflect.DeepEqual(resp.CorporateActions, expectedActions) {
+		t.Errorf("actions %+v not equal expected %+v", resp.CorporateActions, expectedActions)
+	}
+
+	if resp.Points[0].Close != 49 {
+		t.Error("expected first close back-adjusted to: 49, not equal:", resp.Points[0].Close)
+	}
+
+	page, err := c.Get(context.Background(), GetStockArgs{Mode: TimeModeDaily, Symbol: "AAPL", Adjusted: true, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(page.CorporateActions) != 0 {
+		t.Errorf("expected no actions within the first page, got %+v", page.CorporateActions)
+	}
+}
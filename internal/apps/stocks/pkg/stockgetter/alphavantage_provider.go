@@ -3,67 +3,191 @@ package stockgetter
 import (
 	"context"
 	"fmt"
+	"log"
 	"sort"
+	"sync"
+	"time"
 
 	"stockplay/pkg/alphavantage"
 )
 
-const (
-	TimeModeIntraday = iota
-	TimeModeDaily
-	TimeModeWeekly
-	TimeModeMonthly
-
-	TimeInterval1Min = iota + 50
-	TimeInterval5Min
-	TimeInterval15Min
-	TimeInterval30Min
-	TimeInterval60Min
-)
-
 type Point struct {
-	CurrentValue float64 `json:"current_value"`
-	Bid          float64 `json:"bid"`
-	Ask          float64 `json:"ask"`
-	Variation    float64 `json:"variation"`
-	PrevClose    float64 `json:"previous_close"`
-	Open         float64 `json:"open"`
-	Volume       int64   `json:"volume"`
-	Time         int64   `json:"time"`
+	Open      float64 `json:"open"`
+	High      float64 `json:"high"`
+	Low       float64 `json:"low"`
+	Close     float64 `json:"close"`
+	PrevClose float64 `json:"previous_close"`
+
+	// Change is Close minus PrevClose and ChangePercent the same in percent
+	// of PrevClose, 1.5 means 1.5%. Both are zero on the first point.
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+
+	Volume int64 `json:"volume"`
+	Time   int64 `json:"time"`
+	// LocalTime is Time in the zone asked for with InZone, as an RFC 3339
+	// time or a date
+	LocalTime string `json:"local_time,omitempty"`
+
+	// Bid and Ask are only set by providers sending quote data along with
+	// the series
+	Bid *float64 `json:"bid,omitempty"`
+	Ask *float64 `json:"ask,omitempty"`
 }
 
 type Stock struct {
-	Points    []Point `json:"points"`
+	Points []Point `json:"points"`
+	// MarketCap is the shares outstanding times the latest close, zero when
+	// the shares are unknown
 	MarketCap float64 `json:"market_cap"`
-	AvgVolume int64   `json:"avg_volume"`
+	// TradedValue is the sum of close times volume over the points
+	TradedValue float64 `json:"traded_value"`
+	AvgVolume   int64   `json:"avg_volume"`
+
+	// CorporateActions are the dividends and splits within the points of an
+	// adjusted series
+	CorporateActions []CorporateAction `json:"corporate_actions,omitempty"`
+
+	// NextCursor fetches the page after this one, it is empty on the last
+	NextCursor string `json:"next_cursor,omitempty"`
+
+	// Currency is the currency prices were asked in, it is empty when they
+	// are left in the currency the symbol is listed in
+	Currency string `json:"currency,omitempty"`
+	// TimeZone is the zone the LocalTime of the points is in, empty when
+	// they have none
+	TimeZone string `json:"time_zone,omitempty"`
 }
 
 type AlphaVantageClient interface {
 	GetStockTimeSeries(ctx context.Context, args alphavantage.GetStockArgs) ([]alphavantage.Stock, error)
+	GetCryptoTimeSeries(ctx context.Context, args alphavantage.GetCryptoArgs) ([]alphavantage.Stock, error)
+	GetGlobalQuote(ctx context.Context, symbol string) (alphavantage.Quote, error)
+	GetOverview(ctx context.Context, symbol string) (alphavantage.Overview, error)
+	GetIncomeStatement(ctx context.Context, symbol string) (alphavantage.IncomeStatements, error)
+	GetBalanceSheet(ctx context.Context, symbol string) (alphavantage.BalanceSheets, error)
+	GetCashFlow(ctx context.Context, symbol string) (alphavantage.CashFlows, error)
+	GetEarnings(ctx context.Context, symbol string) (alphavantage.EarningsHistory, error)
+	GetFXTimeSeries(ctx context.Context, args alphavantage.GetFXArgs) ([]alphavantage.FXRate, error)
+	GetIndicator(ctx context.Context, args alphavantage.IndicatorArgs) (alphavantage.Indicator, error)
+	SearchSymbols(ctx context.Context, keywords string) ([]alphavantage.SymbolMatch, error)
 }
 
 type AlphaVantageStockGetter struct {
 	client AlphaVantageClient
+	store  SeriesStore
+	now    func() time.Time
+
+	overviewMu sync.Mutex
+	overviews  map[string]overviewEntry
+
+	fxMu    sync.Mutex
+	fxRates map[string]fxEntry
 }
 
-func NewAlphaVantageStockGetter(client AlphaVantageClient) *AlphaVantageStockGetter {
-	return &AlphaVantageStockGetter{client: client}
+// Option configures optional AlphaVantageStockGetter behaviour.
+type Option func(*AlphaVantageStockGetter)
+
+// WithStore writes every fetched series through to store and serves the
+// stored history merged with what upstream returns.
+func WithStore(store SeriesStore) Option {
+	return func(a *AlphaVantageStockGetter) {
+		a.store = store
+	}
+}
+
+func NewAlphaVantageStockGetter(client AlphaVantageClient, opts ...Option) *AlphaVantageStockGetter {
+	a := &AlphaVantageStockGetter{client: client, now: time.Now}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
 }
 
 type GetStockArgs struct {
-	Mode     int
-	Interval int
+	Mode     Mode
+	Interval Interval
 	Symbol   string
+	// Asset is the kind of Symbol, stocks when left empty. Market is the
+	// currency a crypto series is quoted in, DefaultMarket when left empty.
+	Asset  Asset
+	Market string
+
+	// From and To bound the points returned, both ends included, zero
+	// leaves that end open
+	From time.Time
+	To   time.Time
+
+	// Limit caps the points returned from the oldest on, Cursor is the
+	// NextCursor of the previous page
+	Limit  int
+	Cursor string
+
+	// Full fetches the whole history even when the range doesn't reach past
+	// the latest points
+	Full bool
+
+	// Adjusted back-adjusts prices and volumes for splits and dividends and
+	// lists them in the CorporateActions of the series
+	Adjusted bool
+	// Currency converts every price to the given currency code, empty keeps
+	// the currency the symbol is listed in
+	Currency string
+
+	// MarketCap values the series from the company overview, which costs an
+	// upstream call once a day per symbol
+	MarketCap bool
+}
+
+// withDefaults fills the interval of intraday series asked without one and
+// drops it from the others, where it means nothing. Intraday and crypto series
+// have no corporate actions so Adjusted is dropped from them, as is the
+// market from stocks. The market cap is dropped from crypto too, a crypto
+// symbol can name a listed stock whose overview is not the coin's.
+func (args GetStockArgs) withDefaults() GetStockArgs {
+	if args.Asset == "" {
+		args.Asset = AssetStock
+	}
+
+	if args.Asset == AssetCrypto {
+		if args.Market == "" {
+			args.Market = DefaultMarket
+		}
+
+		args.Adjusted = false
+		args.MarketCap = false
+	} else {
+		args.Market = ""
+	}
+
+	if args.Mode != TimeModeIntraday {
+		args.Interval = 0
+		return args
+	}
+
+	if args.Interval == 0 {
+		args.Interval = DefaultInterval
+	}
+	args.Adjusted = false
+
+	return args
 }
 
 func (a *AlphaVantageStockGetter) Get(ctx context.Context, args GetStockArgs) (Stock, error) {
-	resp, err := a.client.GetStockTimeSeries(ctx, alphavantage.GetStockArgs{
-		Mode:     toAVFunc(args.Mode),
-		Interval: toAVInterval(args.Interval),
-		Symbol:   args.Symbol,
-	})
+	args = args.withDefaults()
+	if err := args.validateRange(); err != nil {
+		return Stock{}, err
+	}
+
+	resp, err := a.fetch(ctx, args)
 	if err != nil {
-		return Stock{}, fmt.Errorf("failed to get stock: %w", err)
+		return Stock{}, err
+	}
+
+	if a.store != nil {
+		resp = a.mergeWithStore(seriesKey(args), resp)
 	}
 
 	// before parsing we make sure to sort by date ascending
@@ -71,61 +195,152 @@ func (a *AlphaVantageStockGetter) Get(ctx context.Context, args GetStockArgs) (S
 		return resp[i].Date.Before(resp[j].Date)
 	})
 
-	return parseAVStocks(resp), nil
+	if args.Currency != "" {
+		resp, err = a.convertCurrency(ctx, args, resp)
+		if err != nil {
+			return Stock{}, err
+		}
+	}
+
+	var stock Stock
+	if args.Adjusted {
+		stock = parseAVStocks(backAdjust(resp))
+		stock.CorporateActions = corporateActions(resp)
+	} else {
+		stock = parseAVStocks(resp)
+	}
+
+	if args.MarketCap {
+		a.setMarketCap(ctx, args.Symbol, &stock)
+	}
+	stock.Currency = args.Currency
+
+	return ApplyRange(stock, args)
+}
+
+// fetch gets the series of args from the alphavantage function of its asset.
+func (a *AlphaVantageStockGetter) fetch(ctx context.Context, args GetStockArgs) ([]alphavantage.Stock, error) {
+	var outputSize string
+	if args.needsFull(a.now()) {
+		outputSize = alphavantage.OutputSizeFull
+	}
+
+	if args.Asset == AssetCrypto {
+		avArgs, err := toAVCryptoArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		avArgs.OutputSize = outputSize
+
+		resp, err := a.client.GetCryptoTimeSeries(ctx, avArgs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get crypto: %w", err)
+		}
+
+		return resp, nil
+	}
+
+	avArgs, err := toAVArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	avArgs.OutputSize = outputSize
+
+	resp, err := a.client.GetStockTimeSeries(ctx, avArgs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stock: %w", err)
+	}
+
+	return resp, nil
+}
+
+// mergeWithStore adds the stored history to fetched and persists what's new.
+// The store only adds history, so failing to use it is logged and the fetched
+// points are served as they are.
+func (a *AlphaVantageStockGetter) mergeWithStore(key SeriesKey, fetched []alphavantage.Stock) []alphavantage.Stock {
+	stored, err := a.store.Load(key)
+	if err != nil {
+		log.Println("failed to load stored series", key, err)
+		return fetched
+	}
+
+	merged, changed := mergeStored(stored, fetched)
+	if err := a.store.Append(key, changed); err != nil {
+		log.Println("failed to store series", key, err)
+	}
+
+	return merged
 }
 
 func parseAVStocks(stocks []alphavantage.Stock) Stock {
-	var totalVolume int64
-	var marketCap float64
 	var res Stock
 	var prevClose float64
-	for _, s := range stocks {
-		res.Points = append(res.Points, Point{
-			CurrentValue: s.Close,
-			Bid:          s.High,
-			Ask:          s.Low,
-			Variation:    s.High - s.Low,
-			PrevClose:    prevClose,
-			Open:         s.Open,
-			Volume:       s.Volume,
-			Time:         s.Date.Unix(),
-		})
-
-		totalVolume += s.Volume
-		marketCap += s.Close * float64(s.Volume)
+	for i, s := range stocks {
+		p := Point{
+			Open:   s.Open,
+			High:   s.High,
+			Low:    s.Low,
+			Close:  s.Close,
+			Volume: s.Volume,
+			Time:   s.Date.Unix(),
+		}
+
+		if i > 0 {
+			p.PrevClose = prevClose
+			p.Change = s.Close - prevClose
+			if prevClose != 0 {
+				p.ChangePercent = p.Change / prevClose * 100
+			}
+		}
+
+		res.Points = append(res.Points, p)
 		prevClose = s.Close
 	}
 
-	res.AvgVolume = totalVolume / int64(len(stocks))
-	res.MarketCap = marketCap
+	summarise(&res)
 
 	return res
 }
 
-func toAVInterval(mode int) string {
-	switch mode {
-	case TimeInterval1Min:
-		return alphavantage.Interval1min
-	case TimeInterval15Min:
-		return alphavantage.Interval15min
-	case TimeInterval30Min:
-		return alphavantage.Interval30min
-	case TimeInterval60Min:
-		return alphavantage.Interval60min
+// summarise sets the fields of stock computed over all of its points.
+func summarise(stock *Stock) {
+	var totalVolume int64
+	var tradedValue float64
+	for _, p := range stock.Points {
+		totalVolume += p.Volume
+		tradedValue += p.Close * float64(p.Volume)
 	}
 
-	return alphavantage.Interval5min
+	stock.AvgVolume = 0
+	if len(stock.Points) > 0 {
+		stock.AvgVolume = totalVolume / int64(len(stock.Points))
+	}
+
+	stock.TradedValue = tradedValue
 }
 
-func toAVFunc(mode int) string {
-	switch mode {
-	case TimeModeDaily:
-		return alphavantage.ModeTimeSeriesDaily
-	case TimeModeIntraday:
-		return alphavantage.ModeTimeSeriesIntraday
-	case TimeModeMonthly:
-		return alphavantage.ModeTimeSeriesMonthly
+func toAVArgs(args GetStockArgs) (alphavantage.GetStockArgs, error) {
+	spec, ok := args.Mode.spec()
+	if !ok {
+		return alphavantage.GetStockArgs{}, fmt.Errorf("%s: %w", args.Mode, ErrUnknownMode)
+	}
+
+	mode := spec.avFunction
+	if args.Adjusted {
+		if mode = spec.avAdjustedFunction; mode == "" {
+			return alphavantage.GetStockArgs{}, fmt.Errorf("adjusted %s: %w", args.Mode, ErrUnknownMode)
+		}
+	}
+
+	avArgs := alphavantage.GetStockArgs{Mode: mode, Symbol: args.Symbol}
+	if args.Mode == TimeModeIntraday {
+		interval, ok := args.Interval.spec()
+		if !ok {
+			return alphavantage.GetStockArgs{}, fmt.Errorf("%s: %w", args.Interval, ErrUnknownInterval)
+		}
+
+		avArgs.Interval = interval.avInterval
 	}
 
-	return alphavantage.ModeTimeSeriesWeekly
+	return avArgs, nil
 }
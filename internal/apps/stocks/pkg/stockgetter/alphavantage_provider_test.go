@@ -19,8 +19,120 @@ func (f failAvClient) GetStockTimeSeries(ctx context.Context, args alphavantage.
 	return nil, errors.New("any error")
 }
 
+func (f failAvClient) GetCryptoTimeSeries(ctx context.Context, args alphavantage.GetCryptoArgs) ([]alphavantage.Stock, error) {
+	return nil, errors.New("any error")
+}
+
+func (f failAvClient) GetIndicator(ctx context.Context, args alphavantage.IndicatorArgs) (alphavantage.Indicator, error) {
+	return alphavantage.Indicator{}, errors.New("any error")
+}
+
+func (f failAvClient) GetGlobalQuote(ctx context.Context, symbol string) (alphavantage.Quote, error) {
+	return alphavantage.Quote{}, errors.New("any error")
+}
+
+func (f failAvClient) SearchSymbols(ctx context.Context, keywords string) ([]alphavantage.SymbolMatch, error) {
+	return nil, errors.New("any error")
+}
+
+func (f failAvClient) GetOverview(ctx context.Context, symbol string) (alphavantage.Overview, error) {
+	return alphavantage.Overview{}, errors.New("any error")
+}
+
+func (f failAvClient) GetIncomeStatement(ctx context.Context, symbol string) (alphavantage.IncomeStatements, error) {
+	return alphavantage.IncomeStatements{}, errors.New("any error")
+}
+
+func (f failAvClient) GetBalanceSheet(ctx context.Context, symbol string) (alphavantage.BalanceSheets, error) {
+	return alphavantage.BalanceSheets{}, errors.New("any error")
+}
+
+func (f failAvClient) GetCashFlow(ctx context.Context, symbol string) (alphavantage.CashFlows, error) {
+	return alphavantage.CashFlows{}, errors.New("any error")
+}
+
+func (f failAvClient) GetFXTimeSeries(ctx context.Context, args alphavantage.GetFXArgs) ([]alphavantage.FXRate, error) {
+	return nil, errors.New("any error")
+}
+
+func (f failAvClient) GetEarnings(ctx context.Context, symbol string) (alphavantage.EarningsHistory, error) {
+	return alphavantage.EarningsHistory{}, errors.New("any error")
+}
+
 type successAvClient int
 
+func (s successAvClient) GetOverview(ctx context.Context, symbol string) (alphavantage.Overview, error) {
+	return alphavantage.Overview{Symbol: symbol, Name: "International Business Machines", Currency: "USD", SharesOutstanding: 1000}, nil
+}
+
+func (s successAvClient) GetIncomeStatement(ctx context.Context, symbol string) (alphavantage.IncomeStatements, error) {
+	return alphavantage.IncomeStatements{
+		Symbol: symbol,
+		Annual: []alphavantage.IncomeStatement{
+			{FiscalDateEnding: now, ReportedCurrency: "USD", TotalRevenue: 1000, NetIncome: 100},
+		},
+	}, nil
+}
+
+func (s successAvClient) GetBalanceSheet(ctx context.Context, symbol string) (alphavantage.BalanceSheets, error) {
+	return alphavantage.BalanceSheets{Symbol: symbol}, nil
+}
+
+func (s successAvClient) GetCashFlow(ctx context.Context, symbol string) (alphavantage.CashFlows, error) {
+	return alphavantage.CashFlows{Symbol: symbol}, nil
+}
+
+func (s successAvClient) GetFXTimeSeries(ctx context.Context, args alphavantage.GetFXArgs) ([]alphavantage.FXRate, error) {
+	return []alphavantage.FXRate{{Close: 2, Date: now.AddDate(0, 0, -7)}}, nil
+}
+
+func (s successAvClient) GetEarnings(ctx context.Context, symbol string) (alphavantage.EarningsHistory, error) {
+	return alphavantage.EarningsHistory{
+		Symbol: symbol,
+		Quarterly: []alphavantage.Earnings{
+			{FiscalDateEnding: now, ReportedDate: now, ReportedEPS: 1.5, EstimatedEPS: 1.25, Surprise: 0.25, SurprisePercentage: 20},
+		},
+	}, nil
+}
+
+func (s successAvClient) SearchSymbols(ctx context.Context, keywords string) ([]alphavantage.SymbolMatch, error) {
+	return []alphavantage.SymbolMatch{
+		{Symbol: "IBMM", Name: "iShares iBonds Dec 2026 Term Muni Bond ETF", MatchScore: 0.8},
+		{Symbol: "IBM", Name: "International Business Machines Corp", MatchScore: 1},
+	}, nil
+}
+
+func (s successAvClient) GetCryptoTimeSeries(ctx context.Context, args alphavantage.GetCryptoArgs) ([]alphavantage.Stock, error) {
+	return []alphavantage.Stock{
+		{Open: 30000, High: 31000, Low: 29000, Close: 30500, Volume: 12, Date: now},
+	}, nil
+}
+
+func (s successAvClient) GetIndicator(ctx context.Context, args alphavantage.IndicatorArgs) (alphavantage.Indicator, error) {
+	return alphavantage.Indicator{
+		Columns: []string{"Real Upper Band", "Real Lower Band"},
+		Points: []alphavantage.IndicatorPoint{
+			{Time: now.Add(time.Hour), Values: map[string]float64{"Real Upper Band": 12, "Real Lower Band": 8}},
+			{Time: now, Values: map[string]float64{"Real Upper Band": 11, "Real Lower Band": 9}},
+		},
+	}, nil
+}
+
+func (s successAvClient) GetGlobalQuote(ctx context.Context, symbol string) (alphavantage.Quote, error) {
+	return alphavantage.Quote{
+		Symbol:           symbol,
+		Open:             100.00,
+		High:             110.00,
+		Low:              90.00,
+		Price:            105.00,
+		Volume:           1000,
+		LatestTradingDay: now,
+		PreviousClose:    100.00,
+		Change:           5.00,
+		ChangePercent:    5.00,
+	}, nil
+}
+
 func (s successAvClient) GetStockTimeSeries(ctx context.Context, args alphavantage.GetStockArgs) ([]alphavantage.Stock, error) {
 	return []alphavantage.Stock{
 		{
@@ -45,9 +157,9 @@ func (s successAvClient) GetStockTimeSeries(ctx context.Context, args alphavanta
 func TestAlphaVantageStockGetter_Get(t *testing.T) {
 	var tts = []struct {
 		caseName     string
-		mode         int
+		mode         Mode
 		symbol       string
-		interval     int
+		interval     Interval
 		client       AlphaVantageClient
 		expectedResp Stock
 		expectedErr  bool
@@ -70,28 +182,29 @@ func TestAlphaVantageStockGetter_Get(t *testing.T) {
 			expectedResp: Stock{
 				Points: []Point{
 					{
-						CurrentValue: 80.00,
-						Bid:          90.00,
-						Ask:          10.00,
-						Variation:    90.00 - 10.00,
-						PrevClose:    0,
-						Open:         100.00,
-						Volume:       100,
-						Time:         now.Unix(),
+						Open:      100.00,
+						High:      90.00,
+						Low:       10.00,
+						Close:     80.00,
+						PrevClose: 0,
+						Volume:    100,
+						Time:      now.Unix(),
 					},
 					{
-						CurrentValue: 90.00,
-						Bid:          100.00,
-						Ask:          20.00,
-						Variation:    100.00 - 20.00,
-						PrevClose:    80.00,
-						Open:         200.00,
-						Volume:       110,
-						Time:         now.Add(1 * time.Hour).Unix(),
+						Open:          200.00,
+						High:          100.00,
+						Low:           20.00,
+						Close:         90.00,
+						PrevClose:     80.00,
+						Change:        10.00,
+						ChangePercent: 12.5,
+						Volume:        110,
+						Time:          now.Add(1 * time.Hour).Unix(),
 					},
 				},
-				MarketCap: (80.00 * 100.00) + (90.00 * 110.00),
-				AvgVolume: (100 + 110) / 2,
+				MarketCap:   1000 * 90.00,
+				TradedValue: (80.00 * 100.00) + (90.00 * 110.00),
+				AvgVolume:   (100 + 110) / 2,
 			},
 			expectedErr: false,
 		},
@@ -103,12 +216,14 @@ func TestAlphaVantageStockGetter_Get(t *testing.T) {
 
 		c := AlphaVantageStockGetter{
 			client: tt.client,
+			now:    func() time.Time { return now },
 		}
 
 		resp, err := c.Get(context.Background(), GetStockArgs{
-			Mode:     tt.mode,
-			Interval: tt.interval,
-			Symbol:   tt.symbol,
+			Mode:      tt.mode,
+			Interval:  tt.interval,
+			Symbol:    tt.symbol,
+			MarketCap: true,
 		})
 		if err != nil {
 			if !tt.expectedErr {
@@ -116,7 +231,7 @@ func TestAlphaVantageStockGetter_Get(t *testing.T) {
 			}
 		}
 
-		if resp.MarketCap != tt.expectedResp.MarketCap && resp.AvgVolume != tt.expectedResp.AvgVolume {
+		if resp.MarketCap != tt.expectedResp.MarketCap || resp.TradedValue != tt.expectedResp.TradedValue || resp.AvgVolume != tt.expectedResp.AvgVolume {
 			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
 		}
 
@@ -0,0 +1,250 @@
+package stockgetter
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Getter is anything that can fetch a stock series, CachedStockGetter wraps
+// one to serve repeated requests from memory.
+type Getter interface {
+	Get(ctx context.Context, args GetStockArgs) (Stock, error)
+}
+
+type CacheConfig struct {
+	// MaxEntries bounds the number of cached series, the least recently used
+	// one is evicted first
+	MaxEntries int
+
+	IntradayTTL time.Duration
+	DailyTTL    time.Duration
+	WeeklyTTL   time.Duration
+	MonthlyTTL  time.Duration
+
+	// FetchTimeout bounds a miss going upstream, which outlives the request
+	// that started it so requests waiting on it aren't failed by that one
+	// going away. Zero leaves it unbounded.
+	FetchTimeout time.Duration
+}
+
+// DefaultCacheConfig keeps each series roughly as long as it takes for a new
+// point to show up upstream.
+func DefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		MaxEntries:   256,
+		IntradayTTL:  time.Minute,
+		DailyTTL:     15 * time.Minute,
+		WeeklyTTL:    time.Hour,
+		MonthlyTTL:   6 * time.Hour,
+		FetchTimeout: 30 * time.Second,
+	}
+}
+
+type CacheStats struct {
+	Hits    uint64
+	Misses  uint64
+	Entries int
+}
+
+type cacheEntry struct {
+	key       GetStockArgs
+	stock     Stock
+	expiresAt time.Time
+}
+
+// inflightCall is a miss being fetched, identical misses wait on it instead of
+// going upstream themselves. done is closed once stock and err are set.
+type inflightCall struct {
+	done  chan struct{}
+	stock Stock
+	err   error
+}
+
+type CachedStockGetter struct {
+	next Getter
+	cfg  CacheConfig
+	now  func() time.Time
+
+	mu       sync.Mutex
+	entries  map[GetStockArgs]*list.Element
+	lru      *list.List
+	inflight map[GetStockArgs]*inflightCall
+
+	hits   uint64
+	misses uint64
+}
+
+func NewCachedStockGetter(next Getter, cfg CacheConfig) *CachedStockGetter {
+	return &CachedStockGetter{
+		next:     next,
+		cfg:      cfg,
+		now:      time.Now,
+		entries:  map[GetStockArgs]*list.Element{},
+		lru:      list.New(),
+		inflight: map[GetStockArgs]*inflightCall{},
+	}
+}
+
+// Get serves the whole series from the cache and cuts the range of args out
+// of it, so every range of a series shares one entry.
+func (c *CachedStockGetter) Get(ctx context.Context, args GetStockArgs) (Stock, error) {
+	if err := args.validateRange(); err != nil {
+		return Stock{}, err
+	}
+
+	key := cacheKey(args)
+	key.Full = args.withDefaults().needsFull(c.now())
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if c.now().Before(entry.expiresAt) {
+			c.lru.MoveToFront(elem)
+			c.mu.Unlock()
+
+			atomic.AddUint64(&c.hits, 1)
+			return ApplyRange(copyStock(entry.stock), args)
+		}
+
+		c.removeElement(elem)
+	}
+
+	atomic.AddUint64(&c.misses, 1)
+
+	call, ok := c.inflight[key]
+	if !ok {
+		call = &inflightCall{done: make(chan struct{})}
+		c.inflight[key] = call
+		go c.fetch(ctx, key, call)
+	}
+	c.mu.Unlock()
+
+	// the one starting the call waits like the others, so any of them can
+	// give up on its own ctx without failing the rest
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return Stock{}, ctx.Err()
+	}
+
+	if call.err != nil {
+		return Stock{}, call.err
+	}
+
+	return ApplyRange(copyStock(call.stock), args)
+}
+
+// fetch runs call upstream on a context that keeps the values of ctx but not
+// its cancellation, bounded by FetchTimeout instead.
+func (c *CachedStockGetter) fetch(ctx context.Context, key GetStockArgs, call *inflightCall) {
+	ctx = detachedContext{ctx}
+	if c.cfg.FetchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.FetchTimeout)
+		defer cancel()
+	}
+
+	call.stock, call.err = c.next.Get(ctx, key)
+
+	c.mu.Lock()
+	delete(c.inflight, key)
+	if call.err == nil {
+		c.store(key, call.stock)
+	}
+	c.mu.Unlock()
+
+	close(call.done)
+}
+
+// detachedContext is its parent without the deadline and cancellation.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (d detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+
+func (d detachedContext) Done() <-chan struct{} { return nil }
+
+func (d detachedContext) Err() error { return nil }
+
+func (d detachedContext) Value(key interface{}) interface{} { return d.parent.Value(key) }
+
+func (c *CachedStockGetter) Stats() CacheStats {
+	c.mu.Lock()
+	entries := c.lru.Len()
+	c.mu.Unlock()
+
+	return CacheStats{
+		Hits:    atomic.LoadUint64(&c.hits),
+		Misses:  atomic.LoadUint64(&c.misses),
+		Entries: entries,
+	}
+}
+
+// store must be called with mu held.
+func (c *CachedStockGetter) store(key GetStockArgs, stock Stock) {
+	ttl := c.ttl(key.Mode)
+	if ttl <= 0 || c.cfg.MaxEntries <= 0 {
+		return
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+
+	c.entries[key] = c.lru.PushFront(&cacheEntry{
+		key:       key,
+		stock:     stock,
+		expiresAt: c.now().Add(ttl),
+	})
+
+	for c.lru.Len() > c.cfg.MaxEntries {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+func (c *CachedStockGetter) removeElement(elem *list.Element) {
+	c.lru.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
+
+func (c *CachedStockGetter) ttl(mode Mode) time.Duration {
+	switch mode {
+	case TimeModeIntraday:
+		return c.cfg.IntradayTTL
+	case TimeModeDaily:
+		return c.cfg.DailyTTL
+	case TimeModeMonthly:
+		return c.cfg.MonthlyTTL
+	}
+
+	return c.cfg.WeeklyTTL
+}
+
+// cacheKey normalises args so requests for the same series share an entry,
+// the interval only matters for intraday series.
+func cacheKey(args GetStockArgs) GetStockArgs {
+	args = args.withDefaults().withoutRange()
+	args.Symbol = strings.ToUpper(args.Symbol)
+	args.Market = strings.ToUpper(args.Market)
+	args.Currency = strings.ToUpper(args.Currency)
+
+	return args
+}
+
+// copyStock keeps callers from mutating the cached points.
+func copyStock(stock Stock) Stock {
+	if stock.Points != nil {
+		stock.Points = append([]Point(nil), stock.Points...)
+	}
+
+	if stock.CorporateActions != nil {
+		stock.CorporateActions = append([]CorporateAction(nil), stock.CorporateActions...)
+	}
+
+	return stock
+}
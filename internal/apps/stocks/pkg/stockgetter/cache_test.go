@@ -0,0 +1,221 @@
+package stockgetter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingGetter struct {
+	calls   int64
+	err     error
+	release chan struct{}
+}
+
+func (c *countingGetter) Get(ctx context.Context, args GetStockArgs) (Stock, error) {
+	atomic.AddInt64(&c.calls, 1)
+
+	if c.release != nil {
+		<-c.release
+	}
+
+	if c.err != nil {
+		return Stock{}, c.err
+	}
+
+	return Stock{Points: []Point{{Close: 1}}, AvgVolume: int64(args.Mode)}, nil
+}
+
+func newTestCache(next Getter, maxEntries int) (*CachedStockGetter, *time.Time) {
+	now := time.Date(2020, 11, 6, 20, 0, 0, 0, time.UTC)
+
+	cfg := DefaultCacheConfig()
+	cfg.MaxEntries = maxEntries
+
+	c := NewCachedStockGetter(next, cfg)
+	c.now = func() time.Time { return now }
+
+	return c, &now
+}
+
+func TestCachedStockGetter_HitAndExpiry(t *testing.T) {
+	next := &countingGetter{}
+	c, now := newTestCache(next, 10)
+
+	intraday := GetStockArgs{Mode: TimeModeIntraday, Interval: TimeInterval5Min, Symbol: "ibm"}
+	monthly := GetStockArgs{Mode: TimeModeMonthly, Interval: TimeInterval5Min, Symbol: "ibm"}
+
+	for _, args := range []GetStockArgs{intraday, intraday, monthly, monthly} {
+		if _, err := c.Get(context.Background(), args); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if next.calls != 2 {
+		t.Error("expected upstream calls: 2, not equal:", next.calls)
+	}
+
+	// symbol case and the interval of a non intraday series don't matter
+	if _, err := c.Get(context.Background(), GetStockArgs{Mode: TimeModeMonthly, Interval: TimeInterval60Min, Symbol: "IBM"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if next.calls != 2 {
+		t.Error("expected upstream calls: 2, not equal:", next.calls)
+	}
+
+	// intraday expires well before monthly does
+	*now = now.Add(2 * time.Minute)
+	c.Get(context.Background(), intraday)
+	c.Get(context.Background(), monthly)
+
+	if next.calls != 3 {
+		t.Error("expected upstream calls: 3, not equal:", next.calls)
+	}
+
+	stats := c.Stats()
+	if stats.Hits != 4 || stats.Misses != 3 || stats.Entries != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestCachedStockGetter_Eviction(t *testing.T) {
+	next := &countingGetter{}
+	c, _ := newTestCache(next, 2)
+
+	a := GetStockArgs{Mode: TimeModeDaily, Symbol: "A"}
+	b := GetStockArgs{Mode: TimeModeDaily, Symbol: "B"}
+	d := GetStockArgs{Mode: TimeModeDaily, Symbol: "D"}
+
+	// a is used last so b is the one evicted when d comes in
+	for _, args := range []GetStockArgs{a, b, a, d, a} {
+		c.Get(context.Background(), args)
+	}
+
+	if next.calls != 3 {
+		t.Error("expected upstream calls: 3, not equal:", next.calls)
+	}
+
+	c.Get(context.Background(), b)
+
+	if next.calls != 4 {
+		t.Error("expected upstream calls: 4, not equal:", next.calls)
+	}
+}
+
+func TestCachedStockGetter_ErrorNotCached(t *testing.T) {
+	next := &countingGetter{err: errors.New("any error")}
+	c, _ := newTestCache(next, 10)
+
+	args := GetStockArgs{Mode: TimeModeDaily, Symbol: "A"}
+	for i := 0; i < 2; i++ {
+		if _, err := c.Get(context.Background(), args); err == nil {
+			t.Error("expected error")
+		}
+	}
+
+	if next.calls != 2 {
+		t.Error("expected upstream calls: 2, not equal:", next.calls)
+	}
+}
+
+func TestCachedStockGetter_CollapsesConcurrentMisses(t *testing.T) {
+	next := &countingGetter{release: make(chan struct{})}
+	c, _ := newTestCache(next, 10)
+
+	args := GetStockArgs{Mode: TimeModeDaily, Symbol: "A"}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := c.Get(context.Background(), args); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	// wait for every goroutine to register its miss before letting the
+	// upstream call finish
+	for atomic.LoadUint64(&c.misses) < 10 {
+		time.Sleep(time.Millisecond)
+	}
+	close(next.release)
+	wg.Wait()
+
+	if next.calls != 1 {
+		t.Error("expected upstream calls: 1, not equal:", next.calls)
+	}
+}
+
+type ctxGetter struct {
+	release chan struct{}
+	ctxErr  error
+}
+
+func (c *ctxGetter) Get(ctx context.Context, args GetStockArgs) (Stock, error) {
+	<-c.release
+	c.ctxErr = ctx.Err()
+
+	return Stock{Points: []Point{{Close: 1}}}, nil
+}
+
+func TestCachedStockGetter_CanceledCallerDoesNotFailWaiters(t *testing.T) {
+	next := &ctxGetter{release: make(chan struct{})}
+	c, _ := newTestCache(next, 10)
+
+	args := GetStockArgs{Mode: TimeModeDaily, Symbol: "A"}
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error)
+	go func() {
+		_, err := c.Get(leaderCtx, args)
+		leaderErr <- err
+	}()
+
+	for atomic.LoadUint64(&c.misses) < 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	waiterErr := make(chan error)
+	go func() {
+		_, err := c.Get(context.Background(), args)
+		waiterErr <- err
+	}()
+
+	for atomic.LoadUint64(&c.misses) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the one that started the call leaves right away, without waiting for it
+	cancelLeader()
+	if err := <-leaderErr; err != context.Canceled {
+		t.Error("expected err:", context.Canceled, ", is not err:", err)
+	}
+
+	close(next.release)
+	if err := <-waiterErr; err != nil {
+		t.Error("unexpected err", err)
+	}
+
+	if next.ctxErr != nil {
+		t.Error("expected the upstream call to outlive its caller, got", next.ctxErr)
+	}
+
+	// a waiter leaves on its own ctx as well
+	stuck := &ctxGetter{release: make(chan struct{})}
+	defer close(stuck.release)
+
+	c2, _ := newTestCache(stuck, 10)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := c2.Get(ctx, args); err != context.DeadlineExceeded {
+		t.Error("expected err:", context.DeadlineExceeded, ", is not err:", err)
+	}
+}
@@ -0,0 +1,53 @@
+package stockgetter
+
+import (
+	"fmt"
+
+	"stockplay/pkg/alphavantage"
+)
+
+// Asset is the kind of instrument a symbol names.
+type Asset string
+
+const (
+	AssetStock  Asset = "stock"
+	AssetCrypto Asset = "crypto"
+)
+
+// DefaultMarket is the currency crypto series asked without a market are
+// quoted in.
+const DefaultMarket = "USD"
+
+// Assets lists every supported asset, stocks first.
+func Assets() []Asset {
+	return []Asset{AssetStock, AssetCrypto}
+}
+
+func (a Asset) Valid() bool {
+	for _, asset := range Assets() {
+		if a == asset {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toAVCryptoArgs(args GetStockArgs) (alphavantage.GetCryptoArgs, error) {
+	spec, ok := args.Mode.spec()
+	if !ok || spec.avCryptoFunction == "" {
+		return alphavantage.GetCryptoArgs{}, fmt.Errorf("crypto %s: %w", args.Mode, ErrUnknownMode)
+	}
+
+	avArgs := alphavantage.GetCryptoArgs{Mode: spec.avCryptoFunction, Symbol: args.Symbol, Market: args.Market}
+	if args.Mode == TimeModeIntraday {
+		interval, ok := args.Interval.spec()
+		if !ok {
+			return alphavantage.GetCryptoArgs{}, fmt.Errorf("%s: %w", args.Interval, ErrUnknownInterval)
+		}
+
+		avArgs.Interval = interval.avInterval
+	}
+
+	return avArgs, nil
+}
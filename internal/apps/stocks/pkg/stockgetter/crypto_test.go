@@ -0,0 +1,91 @@
+package stockgetter
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+type cryptoAvClient struct {
+	countingOverviewClient
+	cryptoArgs alphavantage.GetCryptoArgs
+}
+
+func (c *cryptoAvClient) GetCryptoTimeSeries(ctx context.Context, args alphavantage.GetCryptoArgs) ([]alphavantage.Stock, error) {
+	c.cryptoArgs = args
+	return c.successAvClient.GetCryptoTimeSeries(ctx, args)
+}
+
+func TestAlphaVantageStockGetter_GetCrypto(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		args         GetStockArgs
+		expectedArgs alphavantage.GetCryptoArgs
+	}{
+		{
+			caseName:     "when market is left out",
+			args:         GetStockArgs{Mode: TimeModeDaily, Symbol: "BTC", Asset: AssetCrypto, Adjusted: true, MarketCap: true},
+			expectedArgs: alphavantage.GetCryptoArgs{Mode: alphavantage.ModeDigitalCurrencyDaily, Symbol: "BTC", Market: DefaultMarket},
+		},
+		{
+			caseName: "when intraday in a market",
+			args:     GetStockArgs{Mode: TimeModeIntraday, Interval: TimeInterval15Min, Symbol: "ETH", Asset: AssetCrypto, Market: "EUR"},
+			expectedArgs: alphavantage.GetCryptoArgs{
+				Mode:     alphavantage.ModeCryptoIntraday,
+				Interval: alphavantage.Interval15min,
+				Symbol:   "ETH",
+				Market:   "EUR",
+			},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		client := &cryptoAvClient{}
+		c := NewAlphaVantageStockGetter(client)
+		c.now = func() time.Time { return now }
+
+		resp, err := c.Get(context.Background(), tt.args)
+		if err != nil {
+			t.Fatal(logTestcase, err)
+		}
+
+		if client.cryptoArgs != tt.expectedArgs {
+			t.Errorf("%s crypto args %+v not equal expected %+v", logTestcase, client.cryptoArgs, tt.expectedArgs)
+		}
+
+		if len(resp.Points) != 1 || resp.Points[0].Close != 30500 {
+			t.Errorf("%s expected the crypto series, got %+v", logTestcase, resp.Points)
+		}
+
+		if client.calls != 0 || resp.MarketCap != 0 {
+			t.Errorf("%s expected no overview for crypto, calls: %d, market cap: %f", logTestcase, client.calls, resp.MarketCap)
+		}
+	}
+}
+
+func TestFileStore_Path(t *testing.T) {
+	f := FileStore{dir: "data"}
+
+	stock := f.path(SeriesKey{Symbol: "BTC", Asset: AssetStock, Mode: TimeModeDaily})
+	crypto := f.path(SeriesKey{Symbol: "BTC", Asset: AssetCrypto, Market: "usd", Mode: TimeModeDaily})
+
+	if expected := filepath.Join("data", "BTC", "1-0.jsonl"); stock != expected {
+		t.Errorf("stock path [%s] not equal expected [%s]", stock, expected)
+	}
+
+	if expected := filepath.Join("data", "BTC", "crypto-USD-1-0.jsonl"); crypto != expected {
+		t.Errorf("crypto path [%s] not equal expected [%s]", crypto, expected)
+	}
+
+	intraday := f.path(SeriesKey{Symbol: "BTC", Asset: AssetStock, Mode: TimeModeIntraday, Interval: TimeInterval5Min})
+	if expected := filepath.Join("data", "BTC", fmt.Sprintf("0-%d-zoned.jsonl", TimeInterval5Min)); intraday != expected {
+		t.Errorf("intraday path [%s] not equal expected [%s]", intraday, expected)
+	}
+}
@@ -0,0 +1,149 @@
+package stockgetter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+)
+
+// fxTTL is how long the exchange rates of a pair are reused, daily rates only
+// get a new point once a day.
+const fxTTL = 24 * time.Hour
+
+type fxEntry struct {
+	rates     []alphavantage.FXRate
+	full      bool
+	expiresAt time.Time
+}
+
+// convertCurrency prices stocks, sorted by date, in the currency of args
+// instead of the one the symbol is listed or quoted in. Every point is
+// converted at the daily close of its day, or of the last day before it with
+// a rate, and points older than the exchange rate history are left out.
+//
+// Daily rates are used for every mode, intraday ones are a premium endpoint
+// and stamped in UTC while intraday series are stamped in exchange time.
+func (a *AlphaVantageStockGetter) convertCurrency(ctx context.Context, args GetStockArgs, stocks []alphavantage.Stock) ([]alphavantage.Stock, error) {
+	from, err := a.listingCurrency(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(from, args.Currency) {
+		return stocks, nil
+	}
+
+	// the 100 points of a compact series span more than 100 calendar days
+	compact := len(stocks) == 0 || !stocks[0].Date.Before(a.now().AddDate(0, 0, -compactPoints))
+
+	rates, err := a.exchangeRates(ctx, from, args.Currency, compact)
+	if err != nil {
+		return nil, err
+	}
+
+	converted := make([]alphavantage.Stock, 0, len(stocks))
+	next := 0
+	for _, s := range stocks {
+		day := time.Date(s.Date.Year(), s.Date.Month(), s.Date.Day(), 0, 0, 0, 0, time.UTC)
+		for next < len(rates) && !rates[next].Date.After(day) {
+			next++
+		}
+
+		if next == 0 {
+			continue
+		}
+
+		rate := rates[next-1].Close
+		s.Open *= rate
+		s.High *= rate
+		s.Low *= rate
+		s.Close *= rate
+		s.AdjustedClose *= rate
+		s.DividendAmount *= rate
+
+		converted = append(converted, s)
+	}
+
+	return converted, nil
+}
+
+// exchangeRates are the daily rates from one currency to another, sorted by
+// date. They are reused for fxTTL per pair, a compact series only by callers
+// that asked for compact.
+func (a *AlphaVantageStockGetter) exchangeRates(ctx context.Context, from, to string, compact bool) ([]alphavantage.FXRate, error) {
+	key := strings.ToUpper(from + "/" + to)
+
+	a.fxMu.Lock()
+	entry, ok := a.fxRates[key]
+	a.fxMu.Unlock()
+
+	if ok && a.now().Before(entry.expiresAt) && (entry.full || compact) {
+		return entry.rates, nil
+	}
+
+	// a compact series only covers the last 100 days
+	var outputSize string
+	if !compact {
+		outputSize = alphavantage.OutputSizeFull
+	}
+
+	rates, err := a.client.GetFXTimeSeries(ctx, alphavantage.GetFXArgs{
+		Mode:       alphavantage.ModeFXDaily,
+		From:       from,
+		To:         to,
+		OutputSize: outputSize,
+	})
+	if err != nil {
+		if errors.Is(err, alphavantage.ErrInvalidSymbol) {
+			return nil, fmt.Errorf("%w: %s to %s", ErrUnsupportedCurrency, from, to)
+		}
+
+		return nil, fmt.Errorf("failed to get exchange rates: %w", err)
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		return rates[i].Date.Before(rates[j].Date)
+	})
+
+	a.fxMu.Lock()
+	if a.fxRates == nil {
+		a.fxRates = map[string]fxEntry{}
+	}
+	a.fxRates[key] = fxEntry{rates: rates, full: !compact, expiresAt: a.now().Add(fxTTL)}
+	a.fxMu.Unlock()
+
+	return rates, nil
+}
+
+// listingCurrency is the currency the series of args comes in, the market of
+// crypto and the listing currency of stocks.
+func (a *AlphaVantageStockGetter) listingCurrency(ctx context.Context, args GetStockArgs) (string, error) {
+	if args.Asset == AssetCrypto {
+		return args.Market, nil
+	}
+
+	// funds such as SPY have no overview, so no known currency either
+	overview, err := a.GetOverview(ctx, args.Symbol)
+	if errors.Is(err, alphavantage.ErrEmptyOverview) {
+		return "", fmt.Errorf("%w: currency of %s is unknown", ErrUnsupportedCurrency, args.Symbol)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get currency of %s: %w", args.Symbol, err)
+	}
+
+	if overview.Currency == "" {
+		return "", fmt.Errorf("%w: currency of %s is unknown", ErrUnsupportedCurrency, args.Symbol)
+	}
+
+	return overview.Currency, nil
+}
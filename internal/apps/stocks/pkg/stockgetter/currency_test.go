@@ -0,0 +1,151 @@
+package stockgetter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+var fxDay = time.Date(2021, 5, 17, 0, 0, 0, 0, time.UTC)
+
+// fxAvClient serves a daily series from fxDay on, with a weekend, and rates
+// for the days around it.
+type fxAvClient struct {
+	successAvClient
+	fxArgs  alphavantage.GetFXArgs
+	fxErr   error
+	fxCalls int
+}
+
+func (f *fxAvClient) GetStockTimeSeries(ctx context.Context, args alphavantage.GetStockArgs) ([]alphavantage.Stock, error) {
+	return []alphavantage.Stock{
+		{Open: 10, High: 12, Low: 9, Close: 11, Volume: 100, Date: fxDay.AddDate(0, 0, -10)},
+		{Open: 10, High: 12, Low: 9, Close: 11, Volume: 100, Date: fxDay},
+		{Open: 11, High: 13, Low: 10, Close: 12, Volume: 100, Date: fxDay.AddDate(0, 0, 1)},
+		{Open: 12, High: 14, Low: 11, Close: 13, Volume: 100, Date: fxDay.AddDate(0, 0, 7)},
+	}, nil
+}
+
+func (f *fxAvClient) GetFXTimeSeries(ctx context.Context, args alphavantage.GetFXArgs) ([]alphavantage.FXRate, error) {
+	f.fxArgs = args
+	f.fxCalls++
+	if f.fxErr != nil {
+		return nil, f.fxErr
+	}
+
+	return []alphavantage.FXRate{
+		{Close: 0.5, Date: fxDay.AddDate(0, 0, 4)},
+		{Close: 2, Date: fxDay.AddDate(0, 0, 1)},
+		{Close: 1, Date: fxDay},
+	}, nil
+}
+
+func TestAlphaVantageStockGetter_GetInCurrency(t *testing.T) {
+	var tts = []struct {
+		caseName       string
+		currency       string
+		now            time.Time
+		fxErr          error
+		expectedCloses []float64
+		expectedFX     alphavantage.GetFXArgs
+		expectedErr    error
+	}{
+		{
+			caseName:       "when listed in the same currency",
+			currency:       "usd",
+			expectedCloses: []float64{11, 11, 12, 13},
+		},
+		{
+			caseName:       "when converted",
+			currency:       "EUR",
+			expectedCloses: []float64{11, 24, 6.5},
+			expectedFX:     alphavantage.GetFXArgs{Mode: alphavantage.ModeFXDaily, From: "USD", To: "EUR"},
+		},
+		{
+			caseName:       "when converted past the compact rates",
+			currency:       "EUR",
+			now:            fxDay.AddDate(0, 0, 95),
+			expectedCloses: []float64{11, 24, 6.5},
+			expectedFX:     alphavantage.GetFXArgs{Mode: alphavantage.ModeFXDaily, From: "USD", To: "EUR", OutputSize: alphavantage.OutputSizeFull},
+		},
+		{
+			caseName:    "when currency is not offered",
+			currency:    "XYZ",
+			fxErr:       fmt.Errorf("Invalid API call: %w", alphavantage.ErrInvalidSymbol),
+			expectedFX:  alphavantage.GetFXArgs{Mode: alphavantage.ModeFXDaily, From: "USD", To: "XYZ"},
+			expectedErr: ErrUnsupportedCurrency,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		clock := fxDay
+		if !tt.now.IsZero() {
+			clock = tt.now
+		}
+
+		client := &fxAvClient{fxErr: tt.fxErr}
+		c := NewAlphaVantageStockGetter(client)
+		c.now = func() time.Time { return clock }
+
+		resp, err := c.Get(context.Background(), GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM", Currency: tt.currency})
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if client.fxArgs != tt.expectedFX {
+			t.Errorf("%s fx args %+v not equal expected %+v", logTestcase, client.fxArgs, tt.expectedFX)
+		}
+
+		var closes []float64
+		for _, p := range resp.Points {
+			closes = append(closes, p.Close)
+		}
+
+		if !reflect.DeepEqual(closes, tt.expectedCloses) {
+			t.Errorf("%s closes %v not equal expected %v", logTestcase, closes, tt.expectedCloses)
+		}
+
+		if tt.expectedErr == nil && resp.Currency != tt.currency {
+			t.Errorf("%s currency [%s] not equal expected [%s]", logTestcase, resp.Currency, tt.currency)
+		}
+	}
+}
+
+func TestAlphaVantageStockGetter_ExchangeRatesCached(t *testing.T) {
+	clock := fxDay
+	client := &fxAvClient{}
+	c := NewAlphaVantageStockGetter(client)
+	c.now = func() time.Time { return clock }
+
+	get := func(compact bool) {
+		if _, err := c.exchangeRates(context.Background(), "usd", "EUR", compact); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get(true)
+	get(true)
+	if client.fxCalls != 1 {
+		t.Error("expected the rates of the pair to be reused, upstream calls:", client.fxCalls)
+	}
+
+	get(false)
+	get(true)
+	if client.fxCalls != 2 || client.fxArgs.OutputSize != alphavantage.OutputSizeFull {
+		t.Error("expected the full rates fetched once and reused for compact, upstream calls:", client.fxCalls)
+	}
+
+	clock = clock.Add(fxTTL)
+	get(true)
+	if client.fxCalls != 3 || client.fxArgs.OutputSize != "" {
+		t.Error("expected expired rates to be fetched again compact, upstream calls:", client.fxCalls)
+	}
+}
@@ -0,0 +1,232 @@
+package stockgetter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+var (
+	ErrUnknownStatement = errors.New("unknown statement")
+)
+
+// Statement is one of the financial statements a company files.
+type Statement string
+
+const (
+	StatementIncome   Statement = "income"
+	StatementBalance  Statement = "balance"
+	StatementCashFlow Statement = "cashflow"
+	StatementEarnings Statement = "earnings"
+)
+
+const (
+	PeriodAnnual    = "annual"
+	PeriodQuarterly = "quarterly"
+)
+
+// Statements lists every supported statement in display order.
+func Statements() []Statement {
+	return []Statement{StatementIncome, StatementBalance, StatementCashFlow, StatementEarnings}
+}
+
+func (s Statement) Valid() bool {
+	for _, statement := range Statements() {
+		if s == statement {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Items lists the line items reported for the statement, every Report of it
+// carries all of them.
+func (s Statement) Items() []string {
+	switch s {
+	case StatementIncome:
+		return []string{
+			"total_revenue", "cost_of_revenue", "gross_profit", "operating_expenses", "operating_income",
+			"interest_expense", "income_tax_expense", "ebitda", "net_income",
+		}
+	case StatementBalance:
+		return []string{
+			"total_assets", "total_current_assets", "cash_and_equivalents", "inventory", "total_liabilities",
+			"total_current_liabilities", "long_term_debt", "total_shareholder_equity", "shares_outstanding",
+		}
+	case StatementCashFlow:
+		return []string{
+			"operating_cashflow", "capital_expenditures", "cashflow_from_investing", "cashflow_from_financing",
+			"dividend_payout", "net_income",
+		}
+	case StatementEarnings:
+		return []string{"reported_eps", "estimated_eps", "surprise", "surprise_percentage"}
+	}
+
+	return nil
+}
+
+// Report is one fiscal period of a statement. Times are unix seconds,
+// ReportedDate is only known for quarterly earnings.
+type Report struct {
+	Period           string             `json:"period"`
+	FiscalDateEnding int64              `json:"fiscal_date_ending"`
+	ReportedDate     int64              `json:"reported_date,omitempty"`
+	Currency         string             `json:"currency,omitempty"`
+	Items            map[string]float64 `json:"items"`
+}
+
+type Fundamentals struct {
+	Symbol    string    `json:"symbol"`
+	Statement Statement `json:"statement"`
+	Annual    []Report  `json:"annual"`
+	Quarterly []Report  `json:"quarterly"`
+}
+
+func (a *AlphaVantageStockGetter) GetFundamentals(ctx context.Context, symbol string, statement Statement) (Fundamentals, error) {
+	res := Fundamentals{Statement: statement}
+
+	switch statement {
+	case StatementIncome:
+		resp, err := a.client.GetIncomeStatement(ctx, symbol)
+		if err != nil {
+			return Fundamentals{}, fmt.Errorf("failed to get income statement: %w", err)
+		}
+
+		res.Symbol = resp.Symbol
+		res.Annual = incomeReports(PeriodAnnual, resp.Annual)
+		res.Quarterly = incomeReports(PeriodQuarterly, resp.Quarterly)
+	case StatementBalance:
+		resp, err := a.client.GetBalanceSheet(ctx, symbol)
+		if err != nil {
+			return Fundamentals{}, fmt.Errorf("failed to get balance sheet: %w", err)
+		}
+
+		res.Symbol = resp.Symbol
+		res.Annual = balanceReports(PeriodAnnual, resp.Annual)
+		res.Quarterly = balanceReports(PeriodQuarterly, resp.Quarterly)
+	case StatementCashFlow:
+		resp, err := a.client.GetCashFlow(ctx, symbol)
+		if err != nil {
+			return Fundamentals{}, fmt.Errorf("failed to get cash flow: %w", err)
+		}
+
+		res.Symbol = resp.Symbol
+		res.Annual = cashFlowReports(PeriodAnnual, resp.Annual)
+		res.Quarterly = cashFlowReports(PeriodQuarterly, resp.Quarterly)
+	case StatementEarnings:
+		resp, err := a.client.GetEarnings(ctx, symbol)
+		if err != nil {
+			return Fundamentals{}, fmt.Errorf("failed to get earnings: %w", err)
+		}
+
+		res.Symbol = resp.Symbol
+		res.Annual = earningsReports(PeriodAnnual, resp.Annual)
+		res.Quarterly = earningsReports(PeriodQuarterly, resp.Quarterly)
+	default:
+		return Fundamentals{}, fmt.Errorf("%w: %s", ErrUnknownStatement, statement)
+	}
+
+	return res, nil
+}
+
+func incomeReports(period string, statements []alphavantage.IncomeStatement) []Report {
+	reports := make([]Report, 0, len(statements))
+	for _, s := range statements {
+		reports = append(reports, Report{
+			Period:           period,
+			FiscalDateEnding: unixOrZero(s.FiscalDateEnding),
+			Currency:         s.ReportedCurrency,
+			Items: map[string]float64{
+				"total_revenue":      s.TotalRevenue,
+				"cost_of_revenue":    s.CostOfRevenue,
+				"gross_profit":       s.GrossProfit,
+				"operating_expenses": s.OperatingExpenses,
+				"operating_income":   s.OperatingIncome,
+				"interest_expense":   s.InterestExpense,
+				"income_tax_expense": s.IncomeTaxExpense,
+				"ebitda":             s.EBITDA,
+				"net_income":         s.NetIncome,
+			},
+		})
+	}
+
+	return reports
+}
+
+func balanceReports(period string, sheets []alphavantage.BalanceSheet) []Report {
+	reports := make([]Report, 0, len(sheets))
+	for _, s := range sheets {
+		reports = append(reports, Report{
+			Period:           period,
+			FiscalDateEnding: unixOrZero(s.FiscalDateEnding),
+			Currency:         s.ReportedCurrency,
+			Items: map[string]float64{
+				"total_assets":              s.TotalAssets,
+				"total_current_assets":      s.TotalCurrentAssets,
+				"cash_and_equivalents":      s.CashAndEquivalents,
+				"inventory":                 s.Inventory,
+				"total_liabilities":         s.TotalLiabilities,
+				"total_current_liabilities": s.TotalCurrentLiabilities,
+				"long_term_debt":            s.LongTermDebt,
+				"total_shareholder_equity":  s.TotalShareholderEquity,
+				"shares_outstanding":        s.SharesOutstanding,
+			},
+		})
+	}
+
+	return reports
+}
+
+func cashFlowReports(period string, flows []alphavantage.CashFlow) []Report {
+	reports := make([]Report, 0, len(flows))
+	for _, f := range flows {
+		reports = append(reports, Report{
+			Period:           period,
+			FiscalDateEnding: unixOrZero(f.FiscalDateEnding),
+			Currency:         f.ReportedCurrency,
+			Items: map[string]float64{
+				"operating_cashflow":      f.OperatingCashflow,
+				"capital_expenditures":    f.CapitalExpenditures,
+				"cashflow_from_investing": f.CashflowFromInvesting,
+				"cashflow_from_financing": f.CashflowFromFinancing,
+				"dividend_payout":         f.DividendPayout,
+				"net_income":              f.NetIncome,
+			},
+		})
+	}
+
+	return reports
+}
+
+func earningsReports(period string, earnings []alphavantage.Earnings) []Report {
+	reports := make([]Report, 0, len(earnings))
+	for _, e := range earnings {
+		reports = append(reports, Report{
+			Period:           period,
+			FiscalDateEnding: unixOrZero(e.FiscalDateEnding),
+			ReportedDate:     unixOrZero(e.ReportedDate),
+			Items: map[string]float64{
+				"reported_eps":        e.ReportedEPS,
+				"estimated_eps":       e.EstimatedEPS,
+				"surprise":            e.Surprise,
+				"surprise_percentage": e.SurprisePercentage,
+			},
+		})
+	}
+
+	return reports
+}
+
+// unixOrZero keeps an unknown time at zero instead of its negative unix
+// seconds.
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
@@ -0,0 +1,143 @@
+package stockgetter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"stockplay/pkg/alphavantage"
+)
+
+func TestAlphaVantageStockGetter_GetFundamentals(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		client       AlphaVantageClient
+		statement    Statement
+		expectedResp Fundamentals
+		expectedErr  error
+	}{
+		{
+			caseName:     "when response from client error",
+			client:       failAvClient(1),
+			statement:    StatementIncome,
+			expectedResp: Fundamentals{},
+		},
+		{
+			caseName:    "when statement is unknown",
+			client:      successAvClient(1),
+			statement:   Statement("dividends"),
+			expectedErr: ErrUnknownStatement,
+		},
+		{
+			caseName:  "when income statement",
+			client:    successAvClient(1),
+			statement: StatementIncome,
+			expectedResp: Fundamentals{
+				Symbol:    "abcd123",
+				Statement: StatementIncome,
+				Annual: []Report{
+					{
+						Period:           PeriodAnnual,
+						FiscalDateEnding: now.Unix(),
+						Currency:         "USD",
+						Items: map[string]float64{
+							"total_revenue":      1000,
+							"cost_of_revenue":    0,
+							"gross_profit":       0,
+							"operating_expenses": 0,
+							"operating_income":   0,
+							"interest_expense":   0,
+							"income_tax_expense": 0,
+							"ebitda":             0,
+							"net_income":         100,
+						},
+					},
+				},
+				Quarterly: []Report{},
+			},
+		},
+		{
+			caseName:  "when earnings",
+			client:    successAvClient(1),
+			statement: StatementEarnings,
+			expectedResp: Fundamentals{
+				Symbol:    "abcd123",
+				Statement: StatementEarnings,
+				Annual:    []Report{},
+				Quarterly: []Report{
+					{
+						Period:           PeriodQuarterly,
+						FiscalDateEnding: now.Unix(),
+						ReportedDate:     now.Unix(),
+						Items: map[string]float64{
+							"reported_eps":        1.5,
+							"estimated_eps":       1.25,
+							"surprise":            0.25,
+							"surprise_percentage": 20,
+						},
+					},
+				},
+			},
+		},
+		{
+			caseName:  "when dates are missing",
+			client:    undatedAvClient{},
+			statement: StatementEarnings,
+			expectedResp: Fundamentals{
+				Symbol:    "abcd123",
+				Statement: StatementEarnings,
+				Annual:    []Report{},
+				Quarterly: []Report{
+					{
+						Period: PeriodQuarterly,
+						Items: map[string]float64{
+							"reported_eps":        1.5,
+							"estimated_eps":       0,
+							"surprise":            0,
+							"surprise_percentage": 0,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		c := AlphaVantageStockGetter{
+			client: tt.client,
+		}
+
+		resp, err := c.GetFundamentals(context.Background(), "abcd123", tt.statement)
+		if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
+		}
+
+		for _, report := range append(resp.Annual, resp.Quarterly...) {
+			if len(report.Items) != len(tt.statement.Items()) {
+				t.Errorf("%s report items %v don't match the statement items %v", logTestcase, report.Items, tt.statement.Items())
+			}
+		}
+	}
+}
+
+// undatedAvClient serves earnings without a fiscal date ending or a reported
+// date, as alphavantage does with "None".
+type undatedAvClient struct {
+	successAvClient
+}
+
+func (u undatedAvClient) GetEarnings(ctx context.Context, symbol string) (alphavantage.EarningsHistory, error) {
+	return alphavantage.EarningsHistory{
+		Symbol:    symbol,
+		Quarterly: []alphavantage.Earnings{{ReportedEPS: 1.5}},
+	}, nil
+}
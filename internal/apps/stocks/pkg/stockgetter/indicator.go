@@ -0,0 +1,133 @@
+package stockgetter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"stockplay/pkg/alphavantage"
+)
+
+var (
+	ErrUnknownIndicator = errors.New("unknown indicator")
+)
+
+// IndicatorFunction is an alphavantage indicator and the parameters it needs
+// on top of the symbol and interval.
+type IndicatorFunction struct {
+	Name       string
+	TimePeriod bool
+	SeriesType bool
+}
+
+var indicatorFunctions = []IndicatorFunction{
+	{Name: "sma", TimePeriod: true, SeriesType: true},
+	{Name: "ema", TimePeriod: true, SeriesType: true},
+	{Name: "rsi", TimePeriod: true, SeriesType: true},
+	{Name: "stoch"},
+	{Name: "adx", TimePeriod: true},
+	{Name: "cci", TimePeriod: true},
+	{Name: "aroon", TimePeriod: true},
+	{Name: "bbands", TimePeriod: true, SeriesType: true},
+	{Name: "obv"},
+}
+
+// IndicatorFunctions lists the alphavantage indicators served.
+func IndicatorFunctions() []IndicatorFunction {
+	return append([]IndicatorFunction(nil), indicatorFunctions...)
+}
+
+// LookupIndicator finds an indicator function by name, case insensitive.
+func LookupIndicator(name string) (IndicatorFunction, bool) {
+	for _, f := range indicatorFunctions {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+
+	return IndicatorFunction{}, false
+}
+
+// SeriesTypes are the prices an indicator can be computed over.
+func SeriesTypes() []string {
+	return []string{"close", "open", "high", "low"}
+}
+
+type IndicatorArgs struct {
+	Function string
+	Symbol   string
+	Mode     Mode
+	Interval Interval
+	// TimePeriod and SeriesType are left out of functions that don't take
+	// them
+	TimePeriod int
+	SeriesType string
+}
+
+type IndicatorPoint struct {
+	Time   int64              `json:"time"`
+	Values map[string]float64 `json:"values"`
+}
+
+// IndicatorSeries is an indicator as alphavantage computes it, Columns names
+// its lines in order and Points are sorted by time ascending.
+type IndicatorSeries struct {
+	Symbol   string           `json:"symbol"`
+	Function string           `json:"function"`
+	Columns  []string         `json:"columns"`
+	Points   []IndicatorPoint `json:"points"`
+}
+
+func (a *AlphaVantageStockGetter) GetIndicator(ctx context.Context, args IndicatorArgs) (IndicatorSeries, error) {
+	function, ok := LookupIndicator(args.Function)
+	if !ok {
+		return IndicatorSeries{}, fmt.Errorf("%s: %w", args.Function, ErrUnknownIndicator)
+	}
+
+	interval := args.Mode.String()
+	if args.Mode == TimeModeIntraday {
+		spec, ok := args.Interval.spec()
+		if !ok {
+			return IndicatorSeries{}, fmt.Errorf("%s: %w", args.Interval, ErrUnknownInterval)
+		}
+		interval = spec.avInterval
+	} else if !args.Mode.Valid() {
+		return IndicatorSeries{}, fmt.Errorf("%s: %w", args.Mode, ErrUnknownMode)
+	}
+
+	avArgs := alphavantage.IndicatorArgs{
+		Function: strings.ToUpper(function.Name),
+		Symbol:   args.Symbol,
+		Interval: interval,
+	}
+	if function.TimePeriod {
+		avArgs.TimePeriod = args.TimePeriod
+	}
+
+	if function.SeriesType {
+		avArgs.SeriesType = args.SeriesType
+	}
+
+	resp, err := a.client.GetIndicator(ctx, avArgs)
+	if err != nil {
+		return IndicatorSeries{}, fmt.Errorf("failed to get indicator: %w", err)
+	}
+
+	res := IndicatorSeries{
+		Symbol:   args.Symbol,
+		Function: function.Name,
+		Columns:  resp.Columns,
+		Points:   make([]IndicatorPoint, 0, len(resp.Points)),
+	}
+	for _, p := range resp.Points {
+		res.Points = append(res.Points, IndicatorPoint{Time: p.Time.Unix(), Values: p.Values})
+	}
+
+	sort.Slice(res.Points, func(i, j int) bool {
+		return res.Points[i].Time < res.Points[j].Time
+	})
+
+	return res, nil
+}
@@ -0,0 +1,76 @@
+package stockgetter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+type indicatorAvClient struct {
+	successAvClient
+	args alphavantage.IndicatorArgs
+}
+
+func (i *indicatorAvClient) GetIndicator(ctx context.Context, args alphavantage.IndicatorArgs) (alphavantage.Indicator, error) {
+	i.args = args
+	return i.successAvClient.GetIndicator(ctx, args)
+}
+
+func TestAlphaVantageStockGetter_GetIndicator(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		args         IndicatorArgs
+		expectedArgs alphavantage.IndicatorArgs
+		expectedErr  error
+	}{
+		{
+			caseName:    "when function is unknown",
+			args:        IndicatorArgs{Function: "macd", Symbol: "IBM", Mode: TimeModeDaily},
+			expectedErr: ErrUnknownIndicator,
+		},
+		{
+			caseName:     "when daily",
+			args:         IndicatorArgs{Function: "BBands", Symbol: "IBM", Mode: TimeModeDaily, TimePeriod: 20, SeriesType: "close"},
+			expectedArgs: alphavantage.IndicatorArgs{Function: "BBANDS", Symbol: "IBM", Interval: "daily", TimePeriod: 20, SeriesType: "close"},
+		},
+		{
+			caseName:     "when function takes no parameters",
+			args:         IndicatorArgs{Function: "obv", Symbol: "IBM", Mode: TimeModeIntraday, Interval: TimeInterval60Min, TimePeriod: 20, SeriesType: "close"},
+			expectedArgs: alphavantage.IndicatorArgs{Function: "OBV", Symbol: "IBM", Interval: "60min"},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		client := &indicatorAvClient{}
+		c := AlphaVantageStockGetter{client: client, now: func() time.Time { return now }}
+
+		resp, err := c.GetIndicator(context.Background(), tt.args)
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if client.args != tt.expectedArgs {
+			t.Errorf("%s indicator args %+v not equal expected %+v", logTestcase, client.args, tt.expectedArgs)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		expectedPoints := []IndicatorPoint{
+			{Time: now.Unix(), Values: map[string]float64{"Real Upper Band": 11, "Real Lower Band": 9}},
+			{Time: now.Add(time.Hour).Unix(), Values: map[string]float64{"Real Upper Band": 12, "Real Lower Band": 8}},
+		}
+		if !reflect.DeepEqual(resp.Points, expectedPoints) {
+			t.Errorf("%s points %+v not equal expected %+v", logTestcase, resp.Points, expectedPoints)
+		}
+	}
+}
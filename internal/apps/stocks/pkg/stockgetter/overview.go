@@ -0,0 +1,114 @@
+package stockgetter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+// overviewTTL is how long a company overview is reused, shares outstanding
+// only move with filings and every fetch spends upstream budget. Alphavantage
+// saying a symbol has no overview is reused for overviewFailureTTL, symbols
+// such as ETFs would spend it on every request otherwise.
+const (
+	overviewTTL        = 24 * time.Hour
+	overviewFailureTTL = 15 * time.Minute
+)
+
+type Overview struct {
+	Symbol            string  `json:"symbol"`
+	Name              string  `json:"name"`
+	Exchange          string  `json:"exchange"`
+	Currency          string  `json:"currency"`
+	Country           string  `json:"country"`
+	Sector            string  `json:"sector"`
+	Industry          string  `json:"industry"`
+	SharesOutstanding int64   `json:"shares_outstanding"`
+	PERatio           float64 `json:"pe_ratio"`
+	EPS               float64 `json:"eps"`
+	DividendYield     float64 `json:"dividend_yield"`
+	Week52High        float64 `json:"week_52_high"`
+	Week52Low         float64 `json:"week_52_low"`
+}
+
+type overviewEntry struct {
+	overview  Overview
+	err       error
+	expiresAt time.Time
+}
+
+func (a *AlphaVantageStockGetter) GetOverview(ctx context.Context, symbol string) (Overview, error) {
+	key := strings.ToUpper(symbol)
+
+	a.overviewMu.Lock()
+	entry, ok := a.overviews[key]
+	a.overviewMu.Unlock()
+
+	if ok && a.now().Before(entry.expiresAt) {
+		return entry.overview, entry.err
+	}
+
+	resp, err := a.client.GetOverview(ctx, symbol)
+	if err != nil {
+		err = fmt.Errorf("failed to get overview: %w", err)
+
+		// being throttled or timing out says nothing about the symbol
+		if errors.Is(err, alphavantage.ErrEmptyOverview) || errors.Is(err, alphavantage.ErrInvalidSymbol) {
+			a.storeOverview(key, overviewEntry{err: err, expiresAt: a.now().Add(overviewFailureTTL)})
+		}
+
+		return Overview{}, err
+	}
+
+	overview := Overview{
+		Symbol:            resp.Symbol,
+		Name:              resp.Name,
+		Exchange:          resp.Exchange,
+		Currency:          resp.Currency,
+		Country:           resp.Country,
+		Sector:            resp.Sector,
+		Industry:          resp.Industry,
+		SharesOutstanding: resp.SharesOutstanding,
+		PERatio:           resp.PERatio,
+		EPS:               resp.EPS,
+		DividendYield:     resp.DividendYield,
+		Week52High:        resp.Week52High,
+		Week52Low:         resp.Week52Low,
+	}
+
+	a.storeOverview(key, overviewEntry{overview: overview, expiresAt: a.now().Add(overviewTTL)})
+
+	return overview, nil
+}
+
+func (a *AlphaVantageStockGetter) storeOverview(key string, entry overviewEntry) {
+	a.overviewMu.Lock()
+	defer a.overviewMu.Unlock()
+
+	if a.overviews == nil {
+		a.overviews = map[string]overviewEntry{}
+	}
+	a.overviews[key] = entry
+}
+
+// setMarketCap values stock at the shares outstanding times its latest close.
+// The series is worth serving without it, so failing to get the shares is
+// logged and leaves the market cap zero.
+func (a *AlphaVantageStockGetter) setMarketCap(ctx context.Context, symbol string, stock *Stock) {
+	if len(stock.Points) == 0 {
+		return
+	}
+
+	overview, err := a.GetOverview(ctx, symbol)
+	if err != nil {
+		log.Println("failed to get shares outstanding of", symbol, err)
+		return
+	}
+
+	stock.MarketCap = float64(overview.SharesOutstanding) * stock.Points[len(stock.Points)-1].Close
+}
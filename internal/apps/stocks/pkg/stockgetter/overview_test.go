@@ -0,0 +1,141 @@
+package stockgetter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+type countingOverviewClient struct {
+	successAvClient
+	calls int
+}
+
+func (c *countingOverviewClient) GetOverview(ctx context.Context, symbol string) (alphavantage.Overview, error) {
+	c.calls++
+	return c.successAvClient.GetOverview(ctx, symbol)
+}
+
+func TestAlphaVantageStockGetter_GetOverview(t *testing.T) {
+	clock := now
+	client := &countingOverviewClient{}
+	c := NewAlphaVantageStockGetter(client)
+	c.now = func() time.Time { return clock }
+
+	for _, symbol := range []string{"ibm", "IBM"} {
+		overview, err := c.GetOverview(context.Background(), symbol)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if overview.SharesOutstanding != 1000 {
+			t.Error("expected shares outstanding: 1000, not equal:", overview.SharesOutstanding)
+		}
+	}
+
+	if client.calls != 1 {
+		t.Error("expected upstream calls: 1, not equal:", client.calls)
+	}
+
+	clock = clock.Add(overviewTTL)
+	if _, err := c.GetOverview(context.Background(), "IBM"); err != nil {
+		t.Fatal(err)
+	}
+
+	if client.calls != 2 {
+		t.Error("expected an expired overview to be fetched again, upstream calls:", client.calls)
+	}
+}
+
+func TestAlphaVantageStockGetter_GetOverviewFailure(t *testing.T) {
+	var tts = []struct {
+		caseName      string
+		err           error
+		expectedCalls int
+	}{
+		{caseName: "when the symbol has no overview", err: alphavantage.ErrEmptyOverview, expectedCalls: 1},
+		{caseName: "when the symbol is unknown", err: alphavantage.ErrInvalidSymbol, expectedCalls: 1},
+		{caseName: "when rate limited upstream", err: alphavantage.ErrRateLimited, expectedCalls: 2},
+		{caseName: "when over the client budget", err: alphavantage.ErrRateLimitExceeded, expectedCalls: 2},
+		{caseName: "when the server fails", err: fmt.Errorf("status 503: %w", alphavantage.ErrServerResponse), expectedCalls: 2},
+		{caseName: "when the call times out", err: context.DeadlineExceeded, expectedCalls: 2},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		clock := now
+		client := &countingFailOverviewClient{err: tt.err}
+		c := NewAlphaVantageStockGetter(client)
+		c.now = func() time.Time { return clock }
+
+		for i := 0; i < 2; i++ {
+			if _, err := c.GetOverview(context.Background(), "IBM"); !errors.Is(err, tt.err) {
+				t.Error(logTestcase, "expected err:", tt.err, ", is not err:", err)
+			}
+		}
+
+		if client.calls != tt.expectedCalls {
+			t.Errorf("%s upstream calls [%d] not equal expected [%d]", logTestcase, client.calls, tt.expectedCalls)
+		}
+
+		clock = clock.Add(overviewFailureTTL)
+		c.GetOverview(context.Background(), "IBM")
+
+		if client.calls != tt.expectedCalls+1 {
+			t.Errorf("%s expected an expired failure to be fetched again, upstream calls: %d", logTestcase, client.calls)
+		}
+	}
+}
+
+type countingFailOverviewClient struct {
+	failAvClient
+	err   error
+	calls int
+}
+
+func (c *countingFailOverviewClient) GetOverview(ctx context.Context, symbol string) (alphavantage.Overview, error) {
+	c.calls++
+	return alphavantage.Overview{}, c.err
+}
+
+func TestAlphaVantageStockGetter_GetMarketCapOnlyWhenAsked(t *testing.T) {
+	client := &countingOverviewClient{}
+	c := NewAlphaVantageStockGetter(client)
+
+	resp, err := c.Get(context.Background(), GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if client.calls != 0 || resp.MarketCap != 0 {
+		t.Errorf("expected no overview, calls: %d, market cap: %f", client.calls, resp.MarketCap)
+	}
+}
+
+func TestAlphaVantageStockGetter_GetWithoutOverview(t *testing.T) {
+	c := NewAlphaVantageStockGetter(partialAvClient{})
+
+	resp, err := c.Get(context.Background(), GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM", MarketCap: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.MarketCap != 0 || resp.TradedValue == 0 {
+		t.Errorf("expected the series without a market cap, got %+v", resp)
+	}
+}
+
+// partialAvClient serves series but no overview.
+type partialAvClient struct {
+	failAvClient
+}
+
+func (p partialAvClient) GetStockTimeSeries(ctx context.Context, args alphavantage.GetStockArgs) ([]alphavantage.Stock, error) {
+	return successAvClient(1).GetStockTimeSeries(ctx, args)
+}
@@ -0,0 +1,39 @@
+package stockgetter
+
+import (
+	"context"
+	"fmt"
+)
+
+type Quote struct {
+	Symbol           string  `json:"symbol"`
+	Open             float64 `json:"open"`
+	High             float64 `json:"high"`
+	Low              float64 `json:"low"`
+	Price            float64 `json:"price"`
+	Volume           int64   `json:"volume"`
+	LatestTradingDay int64   `json:"latest_trading_day"`
+	PrevClose        float64 `json:"previous_close"`
+	Change           float64 `json:"change"`
+	ChangePercent    float64 `json:"change_percent"`
+}
+
+func (a *AlphaVantageStockGetter) GetQuote(ctx context.Context, symbol string) (Quote, error) {
+	resp, err := a.client.GetGlobalQuote(ctx, symbol)
+	if err != nil {
+		return Quote{}, fmt.Errorf("failed to get quote: %w", err)
+	}
+
+	return Quote{
+		Symbol:           resp.Symbol,
+		Open:             resp.Open,
+		High:             resp.High,
+		Low:              resp.Low,
+		Price:            resp.Price,
+		Volume:           resp.Volume,
+		LatestTradingDay: resp.LatestTradingDay.Unix(),
+		PrevClose:        resp.PreviousClose,
+		Change:           resp.Change,
+		ChangePercent:    resp.ChangePercent,
+	}, nil
+}
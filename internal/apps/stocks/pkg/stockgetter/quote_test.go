@@ -0,0 +1,58 @@
+package stockgetter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestAlphaVantageStockGetter_GetQuote(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		client       AlphaVantageClient
+		expectedResp Quote
+		expectedErr  bool
+	}{
+		{
+			caseName:     "when response from client error",
+			client:       failAvClient(1),
+			expectedResp: Quote{},
+			expectedErr:  true,
+		},
+		{
+			caseName: "when success",
+			client:   successAvClient(1),
+			expectedResp: Quote{
+				Symbol:           "abcd123",
+				Open:             100.00,
+				High:             110.00,
+				Low:              90.00,
+				Price:            105.00,
+				Volume:           1000,
+				LatestTradingDay: now.Unix(),
+				PrevClose:        100.00,
+				Change:           5.00,
+				ChangePercent:    5.00,
+			},
+			expectedErr: false,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		c := AlphaVantageStockGetter{
+			client: tt.client,
+		}
+
+		resp, err := c.GetQuote(context.Background(), "abcd123")
+		if (err != nil) != tt.expectedErr {
+			t.Error(logTestcase, "unexpected err", err)
+		}
+
+		if resp != tt.expectedResp {
+			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
+		}
+	}
+}
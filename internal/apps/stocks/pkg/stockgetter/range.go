@@ -0,0 +1,163 @@
+package stockgetter
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidRange  = errors.New("invalid range")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// compactPoints is how many of the latest points alphavantage sends when the
+// full history isn't asked for.
+const compactPoints = 100
+
+// hasRange reports whether args narrow the series down at all.
+func (args GetStockArgs) hasRange() bool {
+	return !args.From.IsZero() || !args.To.IsZero() || args.Limit > 0 || args.Cursor != ""
+}
+
+// withoutRange is args asking for the whole series the range is cut from.
+func (args GetStockArgs) withoutRange() GetStockArgs {
+	args.From, args.To, args.Limit, args.Cursor = time.Time{}, time.Time{}, 0, ""
+	return args
+}
+
+// Whole is args asking for the whole series its range is cut from, fetched as
+// far back as the range needs. The range is cut out of it with ApplyRange,
+// for callers that need the points around the range as well.
+func (args GetStockArgs) Whole(now time.Time) GetStockArgs {
+	full := args.withDefaults().needsFull(now)
+
+	args = args.withoutRange()
+	args.Full = args.Full || full
+
+	return args
+}
+
+// needsFull reports whether the range reaches further back than the latest
+// compactPoints. A range without from starts at the oldest point of the whole
+// history, so its pages have to be cut from the full series whatever their
+// limit. The reach of a compact series is estimated on calendar time while
+// markets close at night and on weekends, so it errs on fetching more.
+func (args GetStockArgs) needsFull(now time.Time) bool {
+	if args.Full {
+		return true
+	}
+
+	var reach time.Duration
+	switch args.Mode {
+	case TimeModeIntraday:
+		reach = compactPoints * args.Interval.Duration()
+	case TimeModeDaily:
+		// five trading days a week
+		reach = compactPoints * 7 / 5 * 24 * time.Hour
+	default:
+		// weekly and monthly series are always sent whole
+		return false
+	}
+
+	if args.From.IsZero() {
+		return args.hasRange()
+	}
+
+	from := args.From
+	if t, err := decodeCursor(args.Cursor); err == nil && t.After(from) {
+		from = t
+	}
+
+	return from.Before(now.Add(-reach))
+}
+
+func (args GetStockArgs) validateRange() error {
+	if !args.From.IsZero() && !args.To.IsZero() && args.From.After(args.To) {
+		return fmt.Errorf("from is after to: %w", ErrInvalidRange)
+	}
+
+	if args.Limit < 0 {
+		return fmt.Errorf("negative limit: %w", ErrInvalidRange)
+	}
+
+	if _, err := decodeCursor(args.Cursor); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ApplyRange cuts the points of stock down to the range of args. The points
+// must be sorted by time, the summary fields are recomputed over the points
+// that are left.
+func ApplyRange(stock Stock, args GetStockArgs) (Stock, error) {
+	if !args.hasRange() {
+		return stock, nil
+	}
+
+	if err := args.validateRange(); err != nil {
+		return Stock{}, err
+	}
+
+	after, _ := decodeCursor(args.Cursor)
+
+	points := make([]Point, 0, len(stock.Points))
+	for _, p := range stock.Points {
+		t := time.Unix(p.Time, 0)
+		if (!args.From.IsZero() && t.Before(args.From)) || (!args.To.IsZero() && t.After(args.To)) {
+			continue
+		}
+
+		if !after.IsZero() && !t.After(after) {
+			continue
+		}
+
+		points = append(points, p)
+	}
+
+	res := Stock{Points: points, MarketCap: stock.MarketCap, Currency: stock.Currency}
+	if args.Limit > 0 && len(points) > args.Limit {
+		res.Points = points[:args.Limit]
+		res.NextCursor = encodeCursor(res.Points[args.Limit-1].Time)
+	}
+
+	if len(res.Points) > 0 {
+		first, last := res.Points[0].Time, res.Points[len(res.Points)-1].Time
+		for _, a := range stock.CorporateActions {
+			if a.Time >= first && a.Time <= last {
+				res.CorporateActions = append(res.CorporateActions, a)
+			}
+		}
+	}
+
+	summarise(&res)
+
+	return res, nil
+}
+
+// a cursor is the opaque form of the time of the last point of a page, the
+// next page starts right after it.
+func encodeCursor(unix int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(unix, 10)))
+}
+
+func decodeCursor(cursor string) (time.Time, error) {
+	if cursor == "" {
+		return time.Time{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q: %w", cursor, ErrInvalidCursor)
+	}
+
+	unix, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q: %w", cursor, ErrInvalidCursor)
+	}
+
+	return time.Unix(unix, 0), nil
+}
@@ -0,0 +1,278 @@
+package stockgetter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+// dailyAvClient serves one daily point per day ending on end, only the latest
+// 100 unless the full series is asked, and records the args of the last call.
+type dailyAvClient struct {
+	successAvClient
+	end  time.Time
+	days int
+	args alphavantage.GetStockArgs
+}
+
+func (d *dailyAvClient) GetStockTimeSeries(ctx context.Context, args alphavantage.GetStockArgs) ([]alphavantage.Stock, error) {
+	d.args = args
+
+	days := d.days
+	if args.OutputSize != alphavantage.OutputSizeFull && days > compactPoints {
+		days = compactPoints
+	}
+
+	stocks := make([]alphavantage.Stock, 0, days)
+	for i := days - 1; i >= 0; i-- {
+		stocks = append(stocks, alphavantage.Stock{Close: float64(i), Volume: 10, Date: d.end.AddDate(0, 0, -i)})
+	}
+
+	return stocks, nil
+}
+
+func pointTimes(stock Stock) []int64 {
+	times := make([]int64, 0, len(stock.Points))
+	for _, p := range stock.Points {
+		times = append(times, p.Time)
+	}
+
+	return times
+}
+
+func TestAlphaVantageStockGetter_GetRange(t *testing.T) {
+	end := time.Date(2020, 11, 6, 0, 0, 0, 0, time.UTC)
+	day := func(offset int) time.Time {
+		return end.AddDate(0, 0, offset)
+	}
+
+	var tts = []struct {
+		caseName           string
+		args               GetStockArgs
+		expectedTimes      []int64
+		expectedOutputSize string
+		expectedErr        error
+	}{
+		{
+			caseName:      "from and to",
+			args:          GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM", From: day(-3), To: day(-2)},
+			expectedTimes: []int64{day(-3).Unix(), day(-2).Unix()},
+		},
+		{
+			caseName:           "from past the compact series",
+			args:               GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM", From: day(-400), To: day(-399)},
+			expectedTimes:      []int64{day(-400).Unix(), day(-399).Unix()},
+			expectedOutputSize: alphavantage.OutputSizeFull,
+		},
+		{
+			caseName:      "limit over the compact series from a recent day",
+			args:          GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM", From: day(-1), Limit: 101},
+			expectedTimes: []int64{day(-1).Unix(), day(0).Unix()},
+		},
+		{
+			caseName:           "to without from",
+			args:               GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM", To: day(-498)},
+			expectedTimes:      []int64{day(-499).Unix(), day(-498).Unix()},
+			expectedOutputSize: alphavantage.OutputSizeFull,
+		},
+		{
+			caseName:      "limit",
+			args:          GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM", From: day(-2), Limit: 2},
+			expectedTimes: []int64{day(-2).Unix(), day(-1).Unix()},
+		},
+		{
+			caseName:      "cursor",
+			args:          GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM", From: day(-2), Limit: 2, Cursor: encodeCursor(day(-1).Unix())},
+			expectedTimes: []int64{day(0).Unix()},
+		},
+		{
+			caseName:    "from after to",
+			args:        GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM", From: day(0), To: day(-1)},
+			expectedErr: ErrInvalidRange,
+		},
+		{
+			caseName:    "malformed cursor",
+			args:        GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM", Cursor: "%%"},
+			expectedErr: ErrInvalidCursor,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		client := &dailyAvClient{end: end, days: 500}
+		c := AlphaVantageStockGetter{
+			client: client,
+			now:    func() time.Time { return end },
+		}
+
+		resp, err := c.Get(context.Background(), tt.args)
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		if times := pointTimes(resp); !reflect.DeepEqual(times, tt.expectedTimes) {
+			t.Errorf("%s point times %v not equal expected %v", logTestcase, times, tt.expectedTimes)
+		}
+
+		if client.args.OutputSize != tt.expectedOutputSize {
+			t.Errorf("%s output size [%s] not equal expected [%s]", logTestcase, client.args.OutputSize, tt.expectedOutputSize)
+		}
+	}
+}
+
+// barsAvClient serves the same bars whatever is asked.
+type barsAvClient struct {
+	successAvClient
+	bars []alphavantage.Stock
+}
+
+func (b barsAvClient) GetStockTimeSeries(ctx context.Context, args alphavantage.GetStockArgs) ([]alphavantage.Stock, error) {
+	return append([]alphavantage.Stock(nil), b.bars...), nil
+}
+
+func TestAlphaVantageStockGetter_GetIntradayDay(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the extended session of Friday 2020-11-06 closes with the 20:00 bar,
+	// which is already the 7th in UTC
+	bar := func(day, hour int) time.Time {
+		return time.Date(2020, 11, day, hour, 0, 0, 0, newYork)
+	}
+	client := barsAvClient{bars: []alphavantage.Stock{
+		{Close: 1, Date: bar(6, 18)},
+		{Close: 1, Date: bar(6, 19)},
+		{Close: 1, Date: bar(6, 20)},
+		{Close: 1, Date: bar(9, 4)},
+	}}
+
+	var tts = []struct {
+		caseName      string
+		from          time.Time
+		to            time.Time
+		expectedTimes []int64
+	}{
+		{
+			caseName:      "when the day ends in the exchange zone",
+			from:          time.Date(2020, 11, 6, 0, 0, 0, 0, newYork),
+			to:            time.Date(2020, 11, 6, 23, 59, 59, 0, newYork),
+			expectedTimes: []int64{bar(6, 18).Unix(), bar(6, 19).Unix(), bar(6, 20).Unix()},
+		},
+		{
+			caseName:      "when the day ends in UTC",
+			from:          time.Date(2020, 11, 6, 0, 0, 0, 0, time.UTC),
+			to:            time.Date(2020, 11, 6, 23, 59, 59, 0, time.UTC),
+			expectedTimes: []int64{bar(6, 18).Unix()},
+		},
+		{
+			caseName:      "when the next day starts in the exchange zone",
+			from:          time.Date(2020, 11, 7, 0, 0, 0, 0, newYork),
+			expectedTimes: []int64{bar(9, 4).Unix()},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		c := AlphaVantageStockGetter{client: client, now: func() time.Time { return bar(9, 4) }}
+
+		resp, err := c.Get(context.Background(), GetStockArgs{Mode: TimeModeIntraday, Interval: TimeInterval60Min, Symbol: "IBM", From: tt.from, To: tt.to})
+		if err != nil {
+			t.Fatal(logTestcase, err)
+		}
+
+		if times := pointTimes(resp); !reflect.DeepEqual(times, tt.expectedTimes) {
+			t.Errorf("%s point times %v not equal expected %v", logTestcase, times, tt.expectedTimes)
+		}
+	}
+}
+
+func TestAlphaVantageStockGetter_GetPages(t *testing.T) {
+	end := time.Date(2020, 11, 6, 0, 0, 0, 0, time.UTC)
+	client := &dailyAvClient{end: end, days: 5}
+
+	c := NewCachedStockGetter(&AlphaVantageStockGetter{client: client, now: func() time.Time { return end }}, DefaultCacheConfig())
+	c.now = func() time.Time { return end }
+
+	var times []int64
+	args := GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM", Limit: 2}
+	for pages := 0; pages < 5; pages++ {
+		resp, err := c.Get(context.Background(), args)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.AvgVolume != 10 {
+			t.Error("expected avg volume of the page: 10, not equal:", resp.AvgVolume)
+		}
+
+		times = append(times, pointTimes(resp)...)
+		if resp.NextCursor == "" {
+			break
+		}
+
+		args.Cursor = resp.NextCursor
+	}
+
+	if len(times) != 5 || times[0] != end.AddDate(0, 0, -4).Unix() || times[4] != end.Unix() {
+		t.Errorf("paged times %v don't cover the series", times)
+	}
+
+	if stats := c.Stats(); stats.Misses != 1 {
+		t.Error("expected every page served from one cached series, misses:", stats.Misses)
+	}
+}
+
+func TestAlphaVantageStockGetter_GetFirstPage(t *testing.T) {
+	end := time.Date(2020, 11, 6, 0, 0, 0, 0, time.UTC)
+
+	var first []int64
+	for _, limit := range []int{100, 101} {
+		client := &dailyAvClient{end: end, days: 500}
+		c := AlphaVantageStockGetter{client: client, now: func() time.Time { return end }}
+
+		resp, err := c.Get(context.Background(), GetStockArgs{Mode: TimeModeDaily, Symbol: "IBM", Limit: limit})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(resp.Points) != limit {
+			t.Fatalf("limit %d: expected %d points, got %d", limit, limit, len(resp.Points))
+		}
+
+		times := pointTimes(resp)
+		if first == nil {
+			first = times
+			continue
+		}
+
+		if !reflect.DeepEqual(times[:len(first)], first) {
+			t.Errorf("limit %d: first page starts at %d, limit %d at %d", limit, times[0], len(first), first[0])
+		}
+	}
+
+	if first[0] != end.AddDate(0, 0, -499).Unix() {
+		t.Error("expected the first page to start at the oldest point, got", first[0])
+	}
+}
+
+func TestSummariseEmpty(t *testing.T) {
+	stock := parseAVStocks(nil)
+	if stock.AvgVolume != 0 || stock.MarketCap != 0 {
+		t.Errorf("expected an empty summary, got %+v", stock)
+	}
+}
@@ -0,0 +1,49 @@
+package stockgetter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+type SymbolMatch struct {
+	Symbol      string  `json:"symbol"`
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Region      string  `json:"region"`
+	MarketOpen  string  `json:"market_open"`
+	MarketClose string  `json:"market_close"`
+	Timezone    string  `json:"timezone"`
+	Currency    string  `json:"currency"`
+	MatchScore  float64 `json:"match_score"`
+}
+
+// SearchSymbols returns the symbols matching keywords, best match first.
+func (a *AlphaVantageStockGetter) SearchSymbols(ctx context.Context, keywords string) ([]SymbolMatch, error) {
+	resp, err := a.client.SearchSymbols(ctx, keywords)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search symbols: %w", err)
+	}
+
+	matches := make([]SymbolMatch, 0, len(resp))
+	for _, m := range resp {
+		matches = append(matches, SymbolMatch{
+			Symbol:      m.Symbol,
+			Name:        m.Name,
+			Type:        m.Type,
+			Region:      m.Region,
+			MarketOpen:  m.MarketOpen,
+			MarketClose: m.MarketClose,
+			Timezone:    m.Timezone,
+			Currency:    m.Currency,
+			MatchScore:  m.MatchScore,
+		})
+	}
+
+	// upstream usually ranks already, but it is not documented so we make sure
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].MatchScore > matches[j].MatchScore
+	})
+
+	return matches, nil
+}
@@ -0,0 +1,51 @@
+package stockgetter
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestAlphaVantageStockGetter_SearchSymbols(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		client       AlphaVantageClient
+		expectedResp []SymbolMatch
+		expectedErr  bool
+	}{
+		{
+			caseName:     "when response from client error",
+			client:       failAvClient(1),
+			expectedResp: nil,
+			expectedErr:  true,
+		},
+		{
+			caseName: "when success sorted by match score",
+			client:   successAvClient(1),
+			expectedResp: []SymbolMatch{
+				{Symbol: "IBM", Name: "International Business Machines Corp", MatchScore: 1},
+				{Symbol: "IBMM", Name: "iShares iBonds Dec 2026 Term Muni Bond ETF", MatchScore: 0.8},
+			},
+			expectedErr: false,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		c := AlphaVantageStockGetter{
+			client: tt.client,
+		}
+
+		resp, err := c.SearchSymbols(context.Background(), "ibm")
+		if (err != nil) != tt.expectedErr {
+			t.Error(logTestcase, "unexpected err", err)
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
+		}
+	}
+}
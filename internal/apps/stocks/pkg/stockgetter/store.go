@@ -0,0 +1,236 @@
+package stockgetter
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+// SeriesKey identifies one stored series, Interval is only set for intraday
+// and Market for crypto.
+type SeriesKey struct {
+	Symbol   string
+	Asset    Asset
+	Market   string
+	Mode     Mode
+	Interval Interval
+	Adjusted bool
+}
+
+func seriesKey(args GetStockArgs) SeriesKey {
+	args = cacheKey(args)
+
+	return SeriesKey{
+		Symbol:   args.Symbol,
+		Asset:    args.Asset,
+		Market:   args.Market,
+		Mode:     args.Mode,
+		Interval: args.Interval,
+		Adjusted: args.Adjusted,
+	}
+}
+
+// SeriesStore keeps the points fetched upstream so history outlives both the
+// process and what the provider still serves.
+type SeriesStore interface {
+	// Load returns the stored points of key, one per timestamp.
+	Load(key SeriesKey) ([]alphavantage.Stock, error)
+	// Append stores stocks after the points already stored for key, a point
+	// with a timestamp already stored replaces the older one on Load.
+	Append(key SeriesKey, stocks []alphavantage.Stock) error
+}
+
+// storedPoint is the on-disk form of alphavantage.Stock.
+type storedPoint struct {
+	Time   int64   `json:"t"`
+	Open   float64 `json:"o"`
+	High   float64 `json:"h"`
+	Low    float64 `json:"l"`
+	Close  float64 `json:"c"`
+	Volume int64   `json:"v"`
+
+	AdjustedClose    float64 `json:"ac,omitempty"`
+	DividendAmount   float64 `json:"d,omitempty"`
+	SplitCoefficient float64 `json:"s,omitempty"`
+}
+
+// FileStore keeps one append-only segment file of json lines per series under
+// dir/<symbol>/.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create store dir: %w", err)
+	}
+
+	return &FileStore{dir: dir}, nil
+}
+
+func (f *FileStore) Load(key SeriesKey) ([]alphavantage.Stock, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.Open(f.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to open segment: %w", err)
+	}
+	defer file.Close()
+
+	byTime := map[int64]alphavantage.Stock{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var p storedPoint
+		// a crash mid append leaves a torn last line, which is safe to drop
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			continue
+		}
+
+		byTime[p.Time] = alphavantage.Stock{
+			Open:   p.Open,
+			High:   p.High,
+			Low:    p.Low,
+			Close:  p.Close,
+			Volume: p.Volume,
+			Date:   time.Unix(p.Time, 0).UTC(),
+
+			AdjustedClose:    p.AdjustedClose,
+			DividendAmount:   p.DividendAmount,
+			SplitCoefficient: p.SplitCoefficient,
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read segment: %w", err)
+	}
+
+	stocks := make([]alphavantage.Stock, 0, len(byTime))
+	for _, s := range byTime {
+		stocks = append(stocks, s)
+	}
+
+	sort.Slice(stocks, func(i, j int) bool {
+		return stocks[i].Date.Before(stocks[j].Date)
+	})
+
+	return stocks, nil
+}
+
+func (f *FileStore) Append(key SeriesKey, stocks []alphavantage.Stock) error {
+	if len(stocks) == 0 {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := f.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create segment dir: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open segment: %w", err)
+	}
+
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	for _, s := range stocks {
+		err := enc.Encode(storedPoint{
+			Time:   s.Date.Unix(),
+			Open:   s.Open,
+			High:   s.High,
+			Low:    s.Low,
+			Close:  s.Close,
+			Volume: s.Volume,
+
+			AdjustedClose:    s.AdjustedClose,
+			DividendAmount:   s.DividendAmount,
+			SplitCoefficient: s.SplitCoefficient,
+		})
+		if err != nil {
+			file.Close()
+			return fmt.Errorf("failed to encode point: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write segment: %w", err)
+	}
+
+	return file.Close()
+}
+
+func (f *FileStore) path(key SeriesKey) string {
+	symbol := url.PathEscape(strings.ToUpper(key.Symbol))
+	if symbol == "" || strings.Trim(symbol, ".") == "" {
+		symbol = "_" + symbol
+	}
+
+	name := fmt.Sprintf("%d-%d", key.Mode, key.Interval)
+	if key.Adjusted {
+		name += "-adjusted"
+	}
+
+	// stock files keep their original names, but for intraday ones whose
+	// points were stored hours off before they were read in the exchange's
+	// zone. Those files are left behind rather than merged.
+	switch {
+	case key.Asset == AssetCrypto:
+		name = "crypto-" + url.PathEscape(strings.ToUpper(key.Market)) + "-" + name
+	case key.Mode == TimeModeIntraday:
+		name += "-zoned"
+	}
+
+	return filepath.Join(f.dir, symbol, name+".jsonl")
+}
+
+// mergeStored combines the stored points with the ones just fetched, fetched
+// points win on equal timestamps. changed holds the fetched points the store
+// doesn't have yet in that exact form.
+func mergeStored(stored, fetched []alphavantage.Stock) (merged, changed []alphavantage.Stock) {
+	byTime := make(map[int64]alphavantage.Stock, len(stored)+len(fetched))
+	for _, s := range stored {
+		byTime[s.Date.Unix()] = s
+	}
+
+	for _, s := range fetched {
+		old, ok := byTime[s.Date.Unix()]
+		if !ok || !sameStock(old, s) {
+			changed = append(changed, s)
+		}
+
+		byTime[s.Date.Unix()] = s
+	}
+
+	merged = make([]alphavantage.Stock, 0, len(byTime))
+	for _, s := range byTime {
+		merged = append(merged, s)
+	}
+
+	return merged, changed
+}
+
+func sameStock(a, b alphavantage.Stock) bool {
+	return a.Open == b.Open && a.High == b.High && a.Low == b.Low &&
+		a.Close == b.Close && a.Volume == b.Volume && a.Date.Unix() == b.Date.Unix() &&
+		a.AdjustedClose == b.AdjustedClose && a.DividendAmount == b.DividendAmount &&
+		a.SplitCoefficient == b.SplitCoefficient
+}
@@ -0,0 +1,152 @@
+package stockgetter
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+func TestFileStore_AppendLoad(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := SeriesKey{Symbol: "IBM", Mode: TimeModeDaily}
+	day1 := time.Date(2020, 11, 5, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	stocks, err := store.Load(key)
+	if err != nil || len(stocks) != 0 {
+		t.Fatal("expected empty series, got", stocks, err)
+	}
+
+	err = store.Append(key, []alphavantage.Stock{
+		{Close: 10, Date: day2},
+		{Close: 9, Date: day1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// day2 was still trading when first stored, the later close replaces it
+	if err := store.Append(key, []alphavantage.Stock{{Close: 11, Date: day2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	stocks, err = store.Load(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stocks) != 2 || stocks[0].Close != 9 || stocks[1].Close != 11 || !stocks[1].Date.Equal(day2) {
+		t.Errorf("unexpected stored series %+v", stocks)
+	}
+
+	other, err := store.Load(SeriesKey{Symbol: "IBM", Mode: TimeModeWeekly})
+	if err != nil || len(other) != 0 {
+		t.Error("expected series to be stored per mode, got", other, err)
+	}
+}
+
+func TestFileStore_TornLine(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := SeriesKey{Symbol: "IBM", Mode: TimeModeDaily}
+	if err := store.Append(key, []alphavantage.Stock{{Close: 9, Date: time.Unix(0, 0)}}); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.OpenFile(store.path(key), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Write([]byte(`{"t":86400,"o":`))
+	file.Close()
+
+	stocks, err := store.Load(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stocks) != 1 {
+		t.Errorf("expected torn line to be dropped, got %+v", stocks)
+	}
+}
+
+func TestFileStore_PathStaysInDir(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, symbol := range []string{"..", "../../etc", "a/b"} {
+		rel, err := filepath.Rel(dir, store.path(SeriesKey{Symbol: symbol}))
+		if err != nil || filepath.Dir(filepath.Dir(rel)) != "." || filepath.Dir(rel) == ".." {
+			t.Errorf("symbol %s escapes the store dir: %s", symbol, rel)
+		}
+	}
+}
+
+func TestAlphaVantageStockGetter_GetWithStore(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	older := alphavantage.Stock{Close: 70, Volume: 90, Date: now.Add(-1 * time.Hour)}
+	key := SeriesKey{Symbol: "ABCD123", Mode: TimeModeMonthly}
+	if err := store.Append(key, []alphavantage.Stock{older}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewAlphaVantageStockGetter(successAvClient(1), WithStore(store))
+
+	resp, err := c.Get(context.Background(), GetStockArgs{
+		Mode:     TimeModeMonthly,
+		Interval: TimeInterval30Min,
+		Symbol:   "abcd123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Points) != 3 || resp.Points[0].Close != 70 || resp.Points[1].PrevClose != 70 {
+		t.Errorf("expected stored point merged first, got %+v", resp.Points)
+	}
+
+	// the fetched points were written through
+	stored, err := store.Load(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stored) != 3 {
+		t.Errorf("expected 3 stored points, got %+v", stored)
+	}
+
+	// fetching the same points again appends nothing
+	before, _ := ioutil.ReadFile(store.path(key))
+	if _, err := c.Get(context.Background(), GetStockArgs{Mode: TimeModeMonthly, Symbol: "abcd123"}); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := ioutil.ReadFile(store.path(key))
+
+	if len(before) != len(after) {
+		t.Error("expected unchanged points not to be appended again")
+	}
+}
@@ -0,0 +1,223 @@
+package stockgetter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+var (
+	ErrUnknownMode     = errors.New("unknown mode")
+	ErrUnknownInterval = errors.New("unknown interval")
+)
+
+// Mode is the spacing of the points of a series.
+type Mode int
+
+const (
+	TimeModeIntraday Mode = iota
+	TimeModeDaily
+	TimeModeWeekly
+	TimeModeMonthly
+)
+
+// Interval is the spacing of the points of an intraday series.
+type Interval int
+
+// The interval values are the ones the api has always exposed, they have to
+// stay put for callers sending them as numbers.
+const (
+	TimeInterval1Min  Interval = 54
+	TimeInterval5Min  Interval = 55
+	TimeInterval15Min Interval = 56
+	TimeInterval30Min Interval = 57
+	TimeInterval60Min Interval = 58
+)
+
+// DefaultInterval is used for intraday series asked without an interval.
+const DefaultInterval = TimeInterval5Min
+
+// modeSpec is everything known about a mode: the name it is sent and parsed
+// as and the alphavantage functions serving it, empty where there is none.
+type modeSpec struct {
+	mode               Mode
+	name               string
+	avFunction         string
+	avAdjustedFunction string
+	avCryptoFunction   string
+}
+
+// intervalSpec is everything known about an interval: its name, the time
+// between two points and the alphavantage name of the interval.
+type intervalSpec struct {
+	interval   Interval
+	name       string
+	duration   time.Duration
+	avInterval string
+}
+
+// modeSpecs and intervalSpecs are the one table of each, in ascending order.
+// Entries are written with positional fields so one missing a field doesn't
+// compile. Intraday series are sent adjusted already and have no adjusted
+// function.
+var (
+	modeSpecs = []modeSpec{
+		{TimeModeIntraday, "intraday", alphavantage.ModeTimeSeriesIntraday, "", alphavantage.ModeCryptoIntraday},
+		{TimeModeDaily, "daily", alphavantage.ModeTimeSeriesDaily, alphavantage.ModeTimeSeriesDailyAdjusted, alphavantage.ModeDigitalCurrencyDaily},
+		{TimeModeWeekly, "weekly", alphavantage.ModeTimeSeriesWeekly, alphavantage.ModeTimeSeriesWeeklyAdjusted, alphavantage.ModeDigitalCurrencyWeekly},
+		{TimeModeMonthly, "monthly", alphavantage.ModeTimeSeriesMonthly, alphavantage.ModeTimeSeriesMonthlyAdjusted, alphavantage.ModeDigitalCurrencyMonthly},
+	}
+
+	intervalSpecs = []intervalSpec{
+		{TimeInterval1Min, "1min", time.Minute, alphavantage.Interval1min},
+		{TimeInterval5Min, "5min", 5 * time.Minute, alphavantage.Interval5min},
+		{TimeInterval15Min, "15min", 15 * time.Minute, alphavantage.Interval15min},
+		{TimeInterval30Min, "30min", 30 * time.Minute, alphavantage.Interval30min},
+		{TimeInterval60Min, "60min", time.Hour, alphavantage.Interval60min},
+	}
+)
+
+func (m Mode) spec() (modeSpec, bool) {
+	for _, spec := range modeSpecs {
+		if spec.mode == m {
+			return spec, true
+		}
+	}
+
+	return modeSpec{}, false
+}
+
+func (i Interval) spec() (intervalSpec, bool) {
+	for _, spec := range intervalSpecs {
+		if spec.interval == i {
+			return spec, true
+		}
+	}
+
+	return intervalSpec{}, false
+}
+
+// Modes returns every known mode in ascending order.
+func Modes() []Mode {
+	modes := make([]Mode, 0, len(modeSpecs))
+	for _, spec := range modeSpecs {
+		modes = append(modes, spec.mode)
+	}
+
+	return modes
+}
+
+// Intervals returns every known interval from the finest to the coarsest.
+func Intervals() []Interval {
+	intervals := make([]Interval, 0, len(intervalSpecs))
+	for _, spec := range intervalSpecs {
+		intervals = append(intervals, spec.interval)
+	}
+
+	return intervals
+}
+
+func (m Mode) String() string {
+	if spec, ok := m.spec(); ok {
+		return spec.name
+	}
+
+	return fmt.Sprintf("Mode(%d)", int(m))
+}
+
+func (m Mode) Valid() bool {
+	_, ok := m.spec()
+	return ok
+}
+
+// ParseMode accepts a mode name, case insensitive, or its number.
+func ParseMode(s string) (Mode, error) {
+	for _, spec := range modeSpecs {
+		if strings.EqualFold(s, spec.name) || s == strconv.Itoa(int(spec.mode)) {
+			return spec.mode, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%q: %w", s, ErrUnknownMode)
+}
+
+func (m Mode) MarshalJSON() ([]byte, error) {
+	if !m.Valid() {
+		return nil, fmt.Errorf("%d: %w", int(m), ErrUnknownMode)
+	}
+
+	return json.Marshal(m.String())
+}
+
+func (m *Mode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("mode must be a string: %w", err)
+	}
+
+	parsed, err := ParseMode(s)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+func (i Interval) String() string {
+	if spec, ok := i.spec(); ok {
+		return spec.name
+	}
+
+	return fmt.Sprintf("Interval(%d)", int(i))
+}
+
+// Duration is the time between two points, zero for an unknown interval.
+func (i Interval) Duration() time.Duration {
+	spec, _ := i.spec()
+	return spec.duration
+}
+
+func (i Interval) Valid() bool {
+	_, ok := i.spec()
+	return ok
+}
+
+// ParseInterval accepts an interval name, case insensitive, or its number.
+func ParseInterval(s string) (Interval, error) {
+	for _, spec := range intervalSpecs {
+		if strings.EqualFold(s, spec.name) || s == strconv.Itoa(int(spec.interval)) {
+			return spec.interval, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%q: %w", s, ErrUnknownInterval)
+}
+
+func (i Interval) MarshalJSON() ([]byte, error) {
+	if !i.Valid() {
+		return nil, fmt.Errorf("%d: %w", int(i), ErrUnknownInterval)
+	}
+
+	return json.Marshal(i.String())
+}
+
+func (i *Interval) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("interval must be a string: %w", err)
+	}
+
+	parsed, err := ParseInterval(s)
+	if err != nil {
+		return err
+	}
+
+	*i = parsed
+	return nil
+}
@@ -0,0 +1,176 @@
+package stockgetter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"stockplay/pkg/alphavantage"
+)
+
+func TestParseMode(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		value        string
+		expectedMode Mode
+		expectedErr  error
+	}{
+		{caseName: "name", value: "daily", expectedMode: TimeModeDaily},
+		{caseName: "name in another case", value: "Monthly", expectedMode: TimeModeMonthly},
+		{caseName: "number", value: "0", expectedMode: TimeModeIntraday},
+		{caseName: "unknown name", value: "yearly", expectedErr: ErrUnknownMode},
+		{caseName: "unknown number", value: "4", expectedErr: ErrUnknownMode},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		mode, err := ParseMode(tt.value)
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if mode != tt.expectedMode {
+			t.Errorf("%s mode [%s] not equal expected [%s]", logTestcase, mode, tt.expectedMode)
+		}
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	var tts = []struct {
+		caseName         string
+		value            string
+		expectedInterval Interval
+		expectedErr      error
+	}{
+		{caseName: "name", value: "15min", expectedInterval: TimeInterval15Min},
+		{caseName: "number of the original api", value: "55", expectedInterval: TimeInterval5Min},
+		{caseName: "unknown name", value: "2min", expectedErr: ErrUnknownInterval},
+		{caseName: "unknown number", value: "50", expectedErr: ErrUnknownInterval},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		interval, err := ParseInterval(tt.value)
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if interval != tt.expectedInterval {
+			t.Errorf("%s interval [%s] not equal expected [%s]", logTestcase, interval, tt.expectedInterval)
+		}
+	}
+}
+
+func TestTimeframeTables(t *testing.T) {
+	for _, mode := range Modes() {
+		if parsed, err := ParseMode(mode.String()); err != nil || parsed != mode {
+			t.Errorf("mode %s parsed back as %s, err: %v", mode, parsed, err)
+		}
+	}
+
+	var previous time.Duration
+	for _, interval := range Intervals() {
+		if parsed, err := ParseInterval(interval.String()); err != nil || parsed != interval {
+			t.Errorf("interval %s parsed back as %s, err: %v", interval, parsed, err)
+		}
+
+		if interval.Duration() <= previous {
+			t.Errorf("interval %s lasts %s, not longer than the one before it", interval, interval.Duration())
+		}
+		previous = interval.Duration()
+	}
+
+	if d := TimeInterval15Min.Duration(); d != 15*time.Minute {
+		t.Error("expected 15min to last 15 minutes, got", d)
+	}
+
+	if d := Interval(3).Duration(); d != 0 {
+		t.Error("expected an unknown interval to last 0, got", d)
+	}
+}
+
+func TestTimeframeJSON(t *testing.T) {
+	type series struct {
+		Mode     Mode     `json:"mode"`
+		Interval Interval `json:"interval"`
+	}
+
+	in := series{Mode: TimeModeIntraday, Interval: TimeInterval30Min}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != `{"mode":"intraday","interval":"30min"}` {
+		t.Errorf("marshalled [%s] not equal expected", data)
+	}
+
+	var out series
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out != in {
+		t.Errorf("unmarshalled %+v not equal expected %+v", out, in)
+	}
+
+	if _, err := json.Marshal(series{Mode: Mode(9), Interval: TimeInterval1Min}); !errors.Is(err, ErrUnknownMode) {
+		t.Error("expected err:", ErrUnknownMode, ", is not err:", err)
+	}
+}
+
+func TestToAVArgs(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		args         GetStockArgs
+		expectedArgs alphavantage.GetStockArgs
+		expectedErr  error
+	}{
+		{
+			caseName:     "intraday without interval",
+			args:         GetStockArgs{Mode: TimeModeIntraday, Symbol: "IBM"},
+			expectedArgs: alphavantage.GetStockArgs{Mode: alphavantage.ModeTimeSeriesIntraday, Interval: alphavantage.Interval5min, Symbol: "IBM"},
+		},
+		{
+			caseName:     "intraday 60min",
+			args:         GetStockArgs{Mode: TimeModeIntraday, Interval: TimeInterval60Min, Symbol: "IBM"},
+			expectedArgs: alphavantage.GetStockArgs{Mode: alphavantage.ModeTimeSeriesIntraday, Interval: alphavantage.Interval60min, Symbol: "IBM"},
+		},
+		{
+			caseName:     "interval is ignored out of intraday",
+			args:         GetStockArgs{Mode: TimeModeWeekly, Interval: TimeInterval60Min, Symbol: "IBM"},
+			expectedArgs: alphavantage.GetStockArgs{Mode: alphavantage.ModeTimeSeriesWeekly, Symbol: "IBM"},
+		},
+		{
+			caseName:    "unknown mode",
+			args:        GetStockArgs{Mode: Mode(7), Symbol: "IBM"},
+			expectedErr: ErrUnknownMode,
+		},
+		{
+			caseName:    "unknown interval",
+			args:        GetStockArgs{Mode: TimeModeIntraday, Interval: Interval(3), Symbol: "IBM"},
+			expectedErr: ErrUnknownInterval,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		args, err := toAVArgs(tt.args.withDefaults())
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if args != tt.expectedArgs {
+			t.Errorf("%s args %+v not equal expected %+v", logTestcase, args, tt.expectedArgs)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package stockgetter
+
+import (
+	"time"
+)
+
+const layoutDate = "2006-01-02"
+
+// InZone sets the LocalTime of every point of stock to its time in loc.
+// Points of daily and longer modes stand for a trading day, which is kept at
+// midnight UTC, so they are given as that date whatever loc is.
+func InZone(stock Stock, mode Mode, loc *time.Location) Stock {
+	stock = copyStock(stock)
+	stock.TimeZone = loc.String()
+
+	for i, p := range stock.Points {
+		t := time.Unix(p.Time, 0)
+		if mode == TimeModeIntraday {
+			stock.Points[i].LocalTime = t.In(loc).Format(time.RFC3339)
+		} else {
+			stock.Points[i].LocalTime = t.UTC().Format(layoutDate)
+		}
+	}
+
+	return stock
+}
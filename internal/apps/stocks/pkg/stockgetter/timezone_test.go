@@ -0,0 +1,62 @@
+package stockgetter
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestInZone(t *testing.T) {
+	eastern, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tts = []struct {
+		caseName      string
+		mode          Mode
+		times         []time.Time
+		expectedTimes []string
+	}{
+		{
+			caseName: "when intraday across the end of daylight saving",
+			mode:     TimeModeIntraday,
+			times: []time.Time{
+				time.Date(2020, 10, 30, 13, 30, 0, 0, time.UTC),
+				time.Date(2020, 11, 2, 14, 30, 0, 0, time.UTC),
+			},
+			expectedTimes: []string{"2020-10-30T09:30:00-04:00", "2020-11-02T09:30:00-05:00"},
+		},
+		{
+			caseName:      "when daily",
+			mode:          TimeModeDaily,
+			times:         []time.Time{time.Date(2020, 11, 2, 0, 0, 0, 0, time.UTC)},
+			expectedTimes: []string{"2020-11-02"},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		var stock Stock
+		for _, ti := range tt.times {
+			stock.Points = append(stock.Points, Point{Time: ti.Unix()})
+		}
+
+		resp := InZone(stock, tt.mode, eastern)
+		if resp.TimeZone != "America/New_York" {
+			t.Errorf("%s time zone [%s] not equal expected [%s]", logTestcase, resp.TimeZone, "America/New_York")
+		}
+
+		for i, p := range resp.Points {
+			if p.LocalTime != tt.expectedTimes[i] {
+				t.Errorf("%s point %d local time [%s] not equal expected [%s]", logTestcase, i, p.LocalTime, tt.expectedTimes[i])
+			}
+		}
+
+		if stock.Points[0].LocalTime != "" {
+			t.Error(logTestcase, "expected the given points to be left as they are")
+		}
+	}
+}
@@ -0,0 +1,459 @@
+package stocks
+
+import (
+	"crypto/subtle"
+	"encoding/csv"
+	"encoding/json"
+	"log"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"stockplay/internal/apps/stocks/pkg/indicators"
+	"stockplay/internal/apps/stocks/pkg/stockgetter"
+	"stockplay/internal/pkg/apierror"
+)
+
+const (
+	// MediaTypeEncrypted is json encrypted by the encryptor service, it is
+	// what callers get unless they ask for something else
+	MediaTypeEncrypted = "application/x-stockplay-encrypted"
+	MediaTypeJSON      = "application/json"
+	MediaTypeCSV       = "text/csv"
+	MediaTypeNDJSON    = "application/x-ndjson"
+)
+
+// ContentPolicy decides who may receive which response formats.
+type ContentPolicy struct {
+	// PlaintextTokens are the bearer tokens of callers allowed to receive
+	// unencrypted formats, nobody is when it is empty
+	PlaintextTokens []string
+}
+
+func WithContentPolicy(policy ContentPolicy) Option {
+	return func(s *Server) {
+		s.policy = policy
+	}
+}
+
+// authorised reports whether r carries one of the plaintext tokens.
+func (p ContentPolicy) authorised(r *http.Request) bool {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return false
+	}
+
+	token := []byte(strings.TrimPrefix(auth, "Bearer "))
+	for _, t := range p.PlaintextTokens {
+		if subtle.ConstantTimeCompare(token, []byte(t)) == 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// negotiate picks the response format from the Accept header. Plaintext
+// formats are skipped for callers the policy doesn't authorise, it fails when
+// nothing acceptable is left. Handlers call it before doing any work so such
+// a request doesn't cost an upstream call.
+func (s *Server) negotiate(w http.ResponseWriter, r *http.Request) (string, *apierror.Error) {
+	w.Header().Add("Vary", "Accept, Authorization")
+
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return MediaTypeEncrypted, nil
+	}
+
+	type mediaRange struct {
+		format string
+		q      float64
+	}
+
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+
+		var format string
+		switch mediaType {
+		case MediaTypeEncrypted, "*/*", "application/*":
+			format = MediaTypeEncrypted
+		case MediaTypeJSON:
+			format = MediaTypeJSON
+		case MediaTypeCSV, "text/*":
+			format = MediaTypeCSV
+		case MediaTypeNDJSON:
+			format = MediaTypeNDJSON
+		}
+
+		if format != "" && q > 0 {
+			ranges = append(ranges, mediaRange{format: format, q: q})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+
+	forbidden := false
+	for _, mr := range ranges {
+		if mr.format != MediaTypeEncrypted && !s.policy.authorised(r) {
+			forbidden = true
+			continue
+		}
+
+		return mr.format, nil
+	}
+
+	if forbidden {
+		return "", apierror.New(http.StatusForbidden, apierror.CodeForbidden, "plaintext responses are not allowed")
+	}
+
+	return "", apierror.New(http.StatusNotAcceptable, apierror.CodeNotAcceptable,
+		"supported types are "+strings.Join([]string{MediaTypeEncrypted, MediaTypeJSON, MediaTypeCSV, MediaTypeNDJSON}, ", "))
+}
+
+// respond writes v in format, as negotiated with the caller.
+func (s *Server) respond(w http.ResponseWriter, r *http.Request, format string, v interface{}) {
+	switch format {
+	case MediaTypeEncrypted:
+		s.writeEncrypted(w, r, v)
+	case MediaTypeJSON:
+		writeJSON(w, r, v)
+	case MediaTypeCSV, MediaTypeNDJSON:
+		t, ok := v.(table)
+		if !ok {
+			apierror.Write(w, r, apierror.New(http.StatusNotAcceptable, apierror.CodeNotAcceptable, "response can't be written as "+format))
+			return
+		}
+
+		if format == MediaTypeCSV {
+			writeCSV(w, t)
+		} else {
+			writeNDJSON(w, t)
+		}
+	}
+}
+
+// writeEncrypted marshals v to json and writes it encrypted by the encryptor
+// service.
+func (s *Server) writeEncrypted(w http.ResponseWriter, r *http.Request, v interface{}) {
+	text, err := json.Marshal(v)
+	if err != nil {
+		log.Println("got error when marshalling data", err)
+
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "internal error"))
+		return
+	}
+
+	encrypted, err := s.encService.Encrypt(r.Context(), text)
+	if err != nil {
+		log.Println("got error when encrypting data", err)
+
+		apierror.Write(w, r, encryptError(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", MediaTypeEncrypted)
+	w.WriteHeader(http.StatusOK)
+	w.Write(encrypted)
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, v interface{}) {
+	text, err := json.Marshal(v)
+	if err != nil {
+		log.Println("got error when marshalling data", err)
+
+		apierror.Write(w, r, apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "internal error"))
+		return
+	}
+
+	w.Header().Set("Content-Type", MediaTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(text)
+}
+
+// table is implemented by responses made of rows, which can also be written
+// as csv or one json document per line.
+type table interface {
+	header() []string
+	rows() [][]string
+	records() []interface{}
+}
+
+func writeCSV(w http.ResponseWriter, t table) {
+	w.Header().Set("Content-Type", MediaTypeCSV)
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	cw.Write(t.header())
+	cw.WriteAll(t.rows())
+}
+
+func writeNDJSON(w http.ResponseWriter, t table) {
+	w.Header().Set("Content-Type", MediaTypeNDJSON)
+	w.WriteHeader(http.StatusOK)
+
+	enc := json.NewEncoder(w)
+	for _, record := range t.records() {
+		if err := enc.Encode(record); err != nil {
+			log.Println("got error when writing ndjson", err)
+			return
+		}
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatOptional(f *float64) string {
+	if f == nil {
+		return ""
+	}
+
+	return formatFloat(*f)
+}
+
+func formatInt(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
+
+type stockResponse struct {
+	stockgetter.Stock
+	Indicators map[string][]indicators.Value `json:"indicators,omitempty"`
+}
+
+// indicatorNames returns the indicator lines in a stable order.
+func (s stockResponse) indicatorNames() []string {
+	names := make([]string, 0, len(s.Indicators))
+	for name := range s.Indicators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// indicatorsAt returns the value of every indicator line at each point time.
+func (s stockResponse) indicatorsAt() map[int64]map[string]float64 {
+	at := map[int64]map[string]float64{}
+	for name, values := range s.Indicators {
+		for _, v := range values {
+			if at[v.Time] == nil {
+				at[v.Time] = map[string]float64{}
+			}
+
+			at[v.Time][name] = v.Value
+		}
+	}
+
+	return at
+}
+
+// header has a local_time column after time when a zone was asked for.
+func (s stockResponse) header() []string {
+	header := []string{"time"}
+	if s.TimeZone != "" {
+		header = append(header, "local_time")
+	}
+
+	header = append(header,
+		"open", "high", "low", "close", "previous_close", "change", "change_percent", "volume", "bid", "ask",
+	)
+
+	return append(header, s.indicatorNames()...)
+}
+
+func (s stockResponse) rows() [][]string {
+	rows := make([][]string, 0, len(s.Points))
+	for _, p := range s.Points {
+		row := []string{formatInt(p.Time)}
+		if s.TimeZone != "" {
+			row = append(row, p.LocalTime)
+		}
+
+		rows = append(rows, append(row,
+			formatFloat(p.Open),
+			formatFloat(p.High),
+			formatFloat(p.Low),
+			formatFloat(p.Close),
+			formatFloat(p.PrevClose),
+			formatFloat(p.Change),
+			formatFloat(p.ChangePercent),
+			formatInt(p.Volume),
+			formatOptional(p.Bid),
+			formatOptional(p.Ask),
+		))
+	}
+
+	return s.appendIndicators(rows)
+}
+
+// appendIndicators adds the indicator columns to rows, which hold one row per
+// point. Points an indicator is still warming up on are left blank.
+func (s stockResponse) appendIndicators(rows [][]string) [][]string {
+	names := s.indicatorNames()
+	at := s.indicatorsAt()
+
+	for i, p := range s.Points {
+		for _, name := range names {
+			if v, ok := at[p.Time][name]; ok {
+				rows[i] = append(rows[i], formatFloat(v))
+			} else {
+				rows[i] = append(rows[i], "")
+			}
+		}
+	}
+
+	return rows
+}
+
+func (s stockResponse) records() []interface{} {
+	type record struct {
+		stockgetter.Point
+		Indicators map[string]float64 `json:"indicators,omitempty"`
+	}
+
+	at := s.indicatorsAt()
+
+	records := make([]interface{}, 0, len(s.Points))
+	for _, p := range s.Points {
+		records = append(records, record{Point: p, Indicators: at[p.Time]})
+	}
+
+	return records
+}
+
+type quoteResponse stockgetter.Quote
+
+func (q quoteResponse) header() []string {
+	return []string{
+		"symbol", "open", "high", "low", "price", "volume", "latest_trading_day", "previous_close", "change", "change_percent",
+	}
+}
+
+func (q quoteResponse) rows() [][]string {
+	return [][]string{{
+		q.Symbol,
+		formatFloat(q.Open),
+		formatFloat(q.High),
+		formatFloat(q.Low),
+		formatFloat(q.Price),
+		formatInt(q.Volume),
+		formatInt(q.LatestTradingDay),
+		formatFloat(q.PrevClose),
+		formatFloat(q.Change),
+		formatFloat(q.ChangePercent),
+	}}
+}
+
+func (q quoteResponse) records() []interface{} {
+	return []interface{}{q}
+}
+
+// fundamentalsResponse lists the annual reports then the quarterly ones, a row
+// per report with a column per line item.
+type fundamentalsResponse stockgetter.Fundamentals
+
+func (f fundamentalsResponse) header() []string {
+	return append([]string{"period", "fiscal_date_ending", "reported_date", "currency"}, f.Statement.Items()...)
+}
+
+func (f fundamentalsResponse) rows() [][]string {
+	reports := f.reports()
+	rows := make([][]string, 0, len(reports))
+	for _, report := range reports {
+		row := []string{report.Period, formatInt(report.FiscalDateEnding), formatInt(report.ReportedDate), report.Currency}
+		for _, item := range f.Statement.Items() {
+			row = append(row, formatFloat(report.Items[item]))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func (f fundamentalsResponse) records() []interface{} {
+	reports := f.reports()
+	records := make([]interface{}, 0, len(reports))
+	for _, report := range reports {
+		records = append(records, report)
+	}
+
+	return records
+}
+
+func (f fundamentalsResponse) reports() []stockgetter.Report {
+	return append(append([]stockgetter.Report{}, f.Annual...), f.Quarterly...)
+}
+
+type indicatorResponse stockgetter.IndicatorSeries
+
+func (i indicatorResponse) header() []string {
+	return append([]string{"time"}, i.Columns...)
+}
+
+func (i indicatorResponse) rows() [][]string {
+	rows := make([][]string, 0, len(i.Points))
+	for _, p := range i.Points {
+		row := []string{formatInt(p.Time)}
+		for _, column := range i.Columns {
+			row = append(row, formatFloat(p.Values[column]))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+func (i indicatorResponse) records() []interface{} {
+	records := make([]interface{}, 0, len(i.Points))
+	for _, p := range i.Points {
+		records = append(records, p)
+	}
+
+	return records
+}
+
+type searchResponse []stockgetter.SymbolMatch
+
+func (s searchResponse) header() []string {
+	return []string{
+		"symbol", "name", "type", "region", "market_open", "market_close", "timezone", "currency", "match_score",
+	}
+}
+
+func (s searchResponse) rows() [][]string {
+	rows := make([][]string, 0, len(s))
+	for _, m := range s {
+		rows = append(rows, []string{
+			m.Symbol, m.Name, m.Type, m.Region, m.MarketOpen, m.MarketClose, m.Timezone, m.Currency, formatFloat(m.MatchScore),
+		})
+	}
+
+	return rows
+}
+
+func (s searchResponse) records() []interface{} {
+	records := make([]interface{}, 0, len(s))
+	for _, m := range s {
+		records = append(records, m)
+	}
+
+	return records
+}
@@ -0,0 +1,337 @@
+package stocks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"stockplay/internal/apps/stocks/pkg/indicators"
+	"stockplay/internal/apps/stocks/pkg/stockgetter"
+	"stockplay/internal/pkg/apierror"
+)
+
+func TestServer_ContentNegotiation(t *testing.T) {
+	var tts = []struct {
+		caseName            string
+		accept              string
+		token               string
+		enc                 EncryptService
+		expectedStatusCode  int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			caseName:            "when accept is missing",
+			enc:                 successEncSvc(1),
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: MediaTypeEncrypted,
+			expectedBody:        "abcd123",
+		},
+		{
+			caseName:            "when anything is accepted",
+			accept:              "*/*",
+			enc:                 successEncSvc(1),
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: MediaTypeEncrypted,
+			expectedBody:        "abcd123",
+		},
+		{
+			caseName:           "when plaintext is asked without a token",
+			accept:             MediaTypeJSON,
+			enc:                successEncSvc(1),
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       `{"code":"forbidden","message":"plaintext responses are not allowed","request_id":"abcd","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when plaintext is asked with a wrong token",
+			accept:             MediaTypeCSV,
+			token:              "wrong",
+			enc:                successEncSvc(1),
+			expectedStatusCode: http.StatusForbidden,
+			expectedBody:       `{"code":"forbidden","message":"plaintext responses are not allowed","request_id":"abcd","retryable":false}` + "\n",
+		},
+		{
+			caseName:            "when plaintext is preferred but encrypted is acceptable",
+			accept:              MediaTypeJSON + ", " + MediaTypeEncrypted + ";q=0.5",
+			enc:                 successEncSvc(1),
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: MediaTypeEncrypted,
+			expectedBody:        "abcd123",
+		},
+		{
+			caseName:           "when nothing supported is accepted",
+			accept:             "image/png",
+			enc:                successEncSvc(1),
+			expectedStatusCode: http.StatusNotAcceptable,
+			expectedBody:       `{"code":"not_acceptable","message":"supported types are application/x-stockplay-encrypted, application/json, text/csv, application/x-ndjson","request_id":"abcd","retryable":false}` + "\n",
+		},
+		{
+			caseName:            "when json is asked with a token the encryptor isn't needed",
+			accept:              MediaTypeJSON,
+			token:               "secret",
+			enc:                 failEncSvc(1),
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: MediaTypeJSON,
+			expectedBody:        `{"points":[{"current_value":1,"bid":0,"ask":0,"variation":0,"previous_close":0,"open":0,"volume":0,"time":1},{"current_value":3,"bid":0,"ask":0,"variation":0,"previous_close":0,"open":0,"volume":0,"time":2}],"market_cap":0,"avg_volume":0,"indicators":{"sma_2":[{"time":2,"value":2}]}}`,
+		},
+		{
+			caseName:            "when csv is asked with a token",
+			accept:              "text/csv;q=0.9, application/x-ndjson;q=0.1",
+			token:               "secret",
+			enc:                 failEncSvc(1),
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: MediaTypeCSV,
+			expectedBody:        "time,open,current_value,bid,ask,variation,previous_close,volume,sma_2\n1,0,1,0,0,0,0,0,\n2,0,3,0,0,0,0,0,2\n",
+		},
+		{
+			caseName:            "when ndjson is asked with a token",
+			accept:              MediaTypeNDJSON,
+			token:               "secret",
+			enc:                 failEncSvc(1),
+			expectedStatusCode:  http.StatusOK,
+			expectedContentType: MediaTypeNDJSON,
+			expectedBody:        "{\"current_value\":1,\"bid\":0,\"ask\":0,\"variation\":0,\"previous_close\":0,\"open\":0,\"volume\":0,\"time\":1}\n{\"current_value\":3,\"bid\":0,\"ask\":0,\"variation\":0,\"previous_close\":0,\"open\":0,\"volume\":0,\"time\":2,\"indicators\":{\"sma_2\":2}}\n",
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		s := NewServer(pointsStockGetter(1), tt.enc, WithContentPolicy(ContentPolicy{PlaintextTokens: []string{"secret"}}))
+
+		req, err := http.NewRequest(http.MethodGet, "/?symbol=abcd123&indicators=sma:2", nil)
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+
+		req.Header.Set(apierror.RequestIDHeader, "abcd")
+
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+
+		if tt.token != "" {
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+		}
+
+		rw := httptest.NewRecorder()
+
+		s.Router().ServeHTTP(rw, req)
+
+		if rw.Code != tt.expectedStatusCode {
+			t.Errorf("%s status code [%d] not equal expected [%d]", logTestcase, rw.Code, tt.expectedStatusCode)
+		}
+
+		if tt.expectedContentType != "" && rw.Header().Get("Content-Type") != tt.expectedContentType {
+			t.Errorf("%s content type [%s] not equal expected [%s]", logTestcase, rw.Header().Get("Content-Type"), tt.expectedContentType)
+		}
+
+		if rw.Body.String() != tt.expectedBody {
+			t.Errorf("%s body [%s] not equal expected [%s]", logTestcase, rw.Body.String(), tt.expectedBody)
+		}
+	}
+}
+
+func TestServer_Schema(t *testing.T) {
+	var tts = []struct {
+		caseName           string
+		query              string
+		accept             string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			caseName:           "when schema 2 is asked in json",
+			query:              "schema=2",
+			accept:             MediaTypeJSON,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"points":[{"open":0,"high":0,"low":0,"close":1,"previous_close":0,"change":0,"change_percent":0,"volume":0,"time":1},{"open":0,"high":0,"low":0,"close":3,"previous_close":0,"change":0,"change_percent":0,"volume":0,"time":2}],"market_cap":0,"traded_value":0,"avg_volume":0}`,
+		},
+		{
+			caseName:           "when schema 2 is asked in csv",
+			query:              "schema=2&indicators=sma:2",
+			accept:             MediaTypeCSV,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "time,open,high,low,close,previous_close,change,change_percent,volume,bid,ask,sma_2\n1,0,0,0,1,0,0,0,0,,,\n2,0,0,0,3,0,0,0,0,,,2\n",
+		},
+		{
+			caseName:           "when schema 2 is asked in csv with a zone",
+			query:              "schema=2&mode=intraday&tz=Asia/Tokyo",
+			accept:             MediaTypeCSV,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "time,local_time,open,high,low,close,previous_close,change,change_percent,volume,bid,ask\n1,1970-01-01T09:00:01+09:00,0,0,0,1,0,0,0,0,,\n2,1970-01-01T09:00:02+09:00,0,0,0,3,0,0,0,0,,\n",
+		},
+		{
+			caseName:           "when schema 1 is asked in json with a zone",
+			query:              "schema=1&mode=daily&tz=Asia/Tokyo",
+			accept:             MediaTypeJSON,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"points":[{"current_value":1,"bid":0,"ask":0,"variation":0,"previous_close":0,"open":0,"volume":0,"time":1,"local_time":"1970-01-01"},{"current_value":3,"bid":0,"ask":0,"variation":0,"previous_close":0,"open":0,"volume":0,"time":2,"local_time":"1970-01-01"}],"market_cap":0,"avg_volume":0,"time_zone":"Asia/Tokyo"}`,
+		},
+		{
+			caseName:           "when schema is unknown",
+			query:              "schema=3",
+			accept:             MediaTypeJSON,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"schema must be one of 1, 2","request_id":"abcd","retryable":false}` + "\n",
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		s := NewServer(pointsStockGetter(1), failEncSvc(1), WithContentPolicy(ContentPolicy{PlaintextTokens: []string{"secret"}}))
+
+		req, err := http.NewRequest(http.MethodGet, "/?symbol=abcd123&"+tt.query, nil)
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+
+		req.Header.Set(apierror.RequestIDHeader, "abcd")
+		req.Header.Set("Accept", tt.accept)
+		req.Header.Set("Authorization", "Bearer secret")
+
+		rw := httptest.NewRecorder()
+
+		s.Router().ServeHTTP(rw, req)
+
+		if rw.Code != tt.expectedStatusCode {
+			t.Errorf("%s status code [%d] not equal expected [%d]", logTestcase, rw.Code, tt.expectedStatusCode)
+		}
+
+		if rw.Body.String() != tt.expectedBody {
+			t.Errorf("%s body [%s] not equal expected [%s]", logTestcase, rw.Body.String(), tt.expectedBody)
+		}
+	}
+}
+
+func TestServer_NegotiatesBeforeFetching(t *testing.T) {
+	var tts = []struct {
+		caseName           string
+		accept             string
+		expectedStatusCode int
+	}{
+		{caseName: "when nothing supported is accepted", accept: "image/png", expectedStatusCode: http.StatusNotAcceptable},
+		{caseName: "when plaintext is asked without a token", accept: MediaTypeJSON, expectedStatusCode: http.StatusForbidden},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		sg := &recordingStockGetter{}
+		s := NewServer(sg, successEncSvc(1))
+
+		req, err := http.NewRequest(http.MethodGet, "/?symbol=IBM", nil)
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+		req.Header.Set("Accept", tt.accept)
+
+		rw := httptest.NewRecorder()
+
+		s.Router().ServeHTTP(rw, req)
+
+		if rw.Code != tt.expectedStatusCode {
+			t.Errorf("%s status code [%d] not equal expected [%d]", logTestcase, rw.Code, tt.expectedStatusCode)
+		}
+
+		if sg.args.Symbol != "" {
+			t.Error(logTestcase, "expected no upstream call, got one for", sg.args.Symbol)
+		}
+	}
+}
+
+// wholeStockGetter serves ten points closing at 1 to 10 a second apart
+// whatever range is asked, and records the args of the last call.
+type wholeStockGetter struct {
+	args stockgetter.GetStockArgs
+}
+
+func (g *wholeStockGetter) Get(ctx context.Context, args stockgetter.GetStockArgs) (stockgetter.Stock, error) {
+	g.args = args
+
+	var stock stockgetter.Stock
+	for i := 1; i <= 10; i++ {
+		stock.Points = append(stock.Points, stockgetter.Point{Close: float64(i), Time: int64(i)})
+	}
+
+	return stock, nil
+}
+
+func TestServer_IndicatorsOverWholeSeries(t *testing.T) {
+	var tts = []struct {
+		caseName       string
+		query          string
+		expectedTimes  []int64
+		expectedValues []indicators.Value
+	}{
+		{
+			caseName:       "when the page is shorter than the period",
+			query:          "indicators=sma:5&from=1970-01-01T00:00:06Z&limit=3",
+			expectedTimes:  []int64{6, 7, 8},
+			expectedValues: []indicators.Value{{Time: 6, Value: 4}, {Time: 7, Value: 5}, {Time: 8, Value: 6}},
+		},
+		{
+			caseName:       "when the range starts before the period is full",
+			query:          "indicators=sma:5&to=1970-01-01T00:00:06Z",
+			expectedTimes:  []int64{1, 2, 3, 4, 5, 6},
+			expectedValues: []indicators.Value{{Time: 5, Value: 3}, {Time: 6, Value: 4}},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		sg := &wholeStockGetter{}
+		s := NewServer(sg, failEncSvc(1), WithContentPolicy(ContentPolicy{PlaintextTokens: []string{"secret"}}))
+
+		req, err := http.NewRequest(http.MethodGet, "/?symbol=IBM&schema=2&mode=daily&"+tt.query, nil)
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+
+		req.Header.Set("Accept", MediaTypeJSON)
+		req.Header.Set("Authorization", "Bearer secret")
+
+		rw := httptest.NewRecorder()
+
+		s.Router().ServeHTTP(rw, req)
+
+		if rw.Code != http.StatusOK {
+			t.Fatalf("%s status code [%d] not equal expected [%d]", logTestcase, rw.Code, http.StatusOK)
+		}
+
+		if sg.args.Limit != 0 || !sg.args.From.IsZero() || !sg.args.To.IsZero() {
+			t.Errorf("%s expected the whole series to be asked, got %+v", logTestcase, sg.args)
+		}
+
+		var resp struct {
+			Points     []stockgetter.Point           `json:"points"`
+			Indicators map[string][]indicators.Value `json:"indicators"`
+		}
+		if err := json.Unmarshal(rw.Body.Bytes(), &resp); err != nil {
+			t.Fatal(logTestcase, err)
+		}
+
+		var times []int64
+		for _, p := range resp.Points {
+			times = append(times, p.Time)
+		}
+
+		if !reflect.DeepEqual(times, tt.expectedTimes) {
+			t.Errorf("%s point times %v not equal expected %v", logTestcase, times, tt.expectedTimes)
+		}
+
+		if !reflect.DeepEqual(resp.Indicators["sma_5"], tt.expectedValues) {
+			t.Errorf("%s sma values %v not equal expected %v", logTestcase, resp.Indicators["sma_5"], tt.expectedValues)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package stocks
+
+import (
+	"stockplay/internal/apps/stocks/pkg/indicators"
+	"stockplay/internal/apps/stocks/pkg/stockgetter"
+)
+
+const (
+	// SchemaV1 is the original series schema, which named a point's high Bid
+	// and its low Ask, gave its high-low range as Variation and the traded
+	// value as MarketCap. It stays the default until its consumers have moved
+	// to SchemaV2.
+	SchemaV1 = 1
+	SchemaV2 = 2
+)
+
+type pointV1 struct {
+	CurrentValue float64 `json:"current_value"`
+	Bid          float64 `json:"bid"`
+	Ask          float64 `json:"ask"`
+	Variation    float64 `json:"variation"`
+	PrevClose    float64 `json:"previous_close"`
+	Open         float64 `json:"open"`
+	Volume       int64   `json:"volume"`
+	Time         int64   `json:"time"`
+	LocalTime    string  `json:"local_time,omitempty"`
+}
+
+func toPointV1(p stockgetter.Point) pointV1 {
+	return pointV1{
+		CurrentValue: p.Close,
+		Bid:          p.High,
+		Ask:          p.Low,
+		Variation:    p.High - p.Low,
+		PrevClose:    p.PrevClose,
+		Open:         p.Open,
+		Volume:       p.Volume,
+		Time:         p.Time,
+		LocalTime:    p.LocalTime,
+	}
+}
+
+// stockResponseV1 is stockResponse in SchemaV1.
+type stockResponseV1 struct {
+	Points           []pointV1                     `json:"points"`
+	MarketCap        float64                       `json:"market_cap"`
+	AvgVolume        int64                         `json:"avg_volume"`
+	CorporateActions []stockgetter.CorporateAction `json:"corporate_actions,omitempty"`
+	NextCursor       string                        `json:"next_cursor,omitempty"`
+	TimeZone         string                        `json:"time_zone,omitempty"`
+	Indicators       map[string][]indicators.Value `json:"indicators,omitempty"`
+
+	res stockResponse
+}
+
+func newStockResponseV1(res stockResponse) stockResponseV1 {
+	points := make([]pointV1, 0, len(res.Points))
+	for _, p := range res.Points {
+		points = append(points, toPointV1(p))
+	}
+
+	return stockResponseV1{
+		Points:           points,
+		MarketCap:        res.TradedValue,
+		AvgVolume:        res.AvgVolume,
+		CorporateActions: res.CorporateActions,
+		NextCursor:       res.NextCursor,
+		TimeZone:         res.TimeZone,
+		Indicators:       res.Indicators,
+		res:              res,
+	}
+}
+
+func (s stockResponseV1) header() []string {
+	header := []string{"time"}
+	if s.TimeZone != "" {
+		header = append(header, "local_time")
+	}
+
+	header = append(header, "open", "current_value", "bid", "ask", "variation", "previous_close", "volume")
+
+	return append(header, s.res.indicatorNames()...)
+}
+
+func (s stockResponseV1) rows() [][]string {
+	rows := make([][]string, 0, len(s.Points))
+	for _, p := range s.Points {
+		row := []string{formatInt(p.Time)}
+		if s.TimeZone != "" {
+			row = append(row, p.LocalTime)
+		}
+
+		rows = append(rows, append(row,
+			formatFloat(p.Open),
+			formatFloat(p.CurrentValue),
+			formatFloat(p.Bid),
+			formatFloat(p.Ask),
+			formatFloat(p.Variation),
+			formatFloat(p.PrevClose),
+			formatInt(p.Volume),
+		))
+	}
+
+	return s.res.appendIndicators(rows)
+}
+
+func (s stockResponseV1) records() []interface{} {
+	type record struct {
+		pointV1
+		Indicators map[string]float64 `json:"indicators,omitempty"`
+	}
+
+	at := s.res.indicatorsAt()
+
+	records := make([]interface{}, 0, len(s.Points))
+	for _, p := range s.Points {
+		records = append(records, record{pointV1: p, Indicators: at[p.Time]})
+	}
+
+	return records
+}
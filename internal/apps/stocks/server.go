@@ -3,87 +3,493 @@ package stocks
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
-	"strconv"
+	"time"
 
+	"stockplay/internal/apps/encryptor/pkg/client"
+	"stockplay/internal/apps/stocks/pkg/indicators"
 	"stockplay/internal/apps/stocks/pkg/stockgetter"
+	"stockplay/internal/pkg/apierror"
+	"stockplay/pkg/alphavantage"
 )
 
 type StockGetter interface {
 	Get(ctx context.Context, args stockgetter.GetStockArgs) (stockgetter.Stock, error)
 }
 
+type QuoteGetter interface {
+	GetQuote(ctx context.Context, symbol string) (stockgetter.Quote, error)
+}
+
+type FundamentalsGetter interface {
+	GetFundamentals(ctx context.Context, symbol string, statement stockgetter.Statement) (stockgetter.Fundamentals, error)
+}
+
+type IndicatorGetter interface {
+	GetIndicator(ctx context.Context, args stockgetter.IndicatorArgs) (stockgetter.IndicatorSeries, error)
+}
+
+type SymbolSearcher interface {
+	SearchSymbols(ctx context.Context, keywords string) ([]stockgetter.SymbolMatch, error)
+}
+
+type BudgetReporter interface {
+	RemainingBudget() (alphavantage.Budget, bool)
+}
+
 type EncryptService interface {
 	Encrypt(ctx context.Context, text []byte) ([]byte, error)
 }
 
 type Server struct {
 	stockGetter StockGetter
+	quoteGetter QuoteGetter
+	searcher    SymbolSearcher
+	budget      BudgetReporter
 	encService  EncryptService
+	policy      ContentPolicy
+
+	fundamentalsGetter FundamentalsGetter
+	indicatorGetter    IndicatorGetter
 }
 
-func NewServer(stockGetter StockGetter, encService EncryptService) *Server {
-	return &Server{
+// Option configures the optional dependencies of a Server, routes backed by a
+// dependency that is not set are not served.
+type Option func(*Server)
+
+func WithQuoteGetter(quoteGetter QuoteGetter) Option {
+	return func(s *Server) {
+		s.quoteGetter = quoteGetter
+	}
+}
+
+func WithFundamentalsGetter(fundamentalsGetter FundamentalsGetter) Option {
+	return func(s *Server) {
+		s.fundamentalsGetter = fundamentalsGetter
+	}
+}
+
+func WithIndicatorGetter(indicatorGetter IndicatorGetter) Option {
+	return func(s *Server) {
+		s.indicatorGetter = indicatorGetter
+	}
+}
+
+func WithSymbolSearcher(searcher SymbolSearcher) Option {
+	return func(s *Server) {
+		s.searcher = searcher
+	}
+}
+
+func WithBudgetReporter(budget BudgetReporter) Option {
+	return func(s *Server) {
+		s.budget = budget
+	}
+}
+
+func NewServer(stockGetter StockGetter, encService EncryptService, opts ...Option) *Server {
+	s := &Server{
 		stockGetter: stockGetter,
 		encService:  encService,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Router serves the stock series at the root path and every optional route
+// whose dependency is configured.
+func (s *Server) Router() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", s.HandleGetStock())
+
+	if s.quoteGetter != nil {
+		mux.Handle("/quote", s.HandleGetQuote())
+	}
+
+	if s.fundamentalsGetter != nil {
+		mux.Handle("/fundamentals", s.HandleGetFundamentals())
+	}
+
+	if s.indicatorGetter != nil {
+		mux.Handle("/indicator", s.HandleGetIndicator())
+	}
+
+	if s.searcher != nil {
+		mux.Handle("/search", s.HandleSearchSymbols())
+	}
+
+	if s.budget != nil {
+		mux.Handle("/budget", s.HandleGetBudget())
+	}
+
+	return apierror.WithRequestID(mux)
 }
 
 func (s *Server) HandleGetStock() http.HandlerFunc {
 	const (
 		defaultMode     = stockgetter.TimeModeWeekly
-		defaultInterval = stockgetter.TimeInterval60Min
+		defaultInterval = stockgetter.DefaultInterval
 	)
 
 	return func(w http.ResponseWriter, r *http.Request) {
+		format, apiErr := s.negotiate(w, r)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
 		q := r.URL.Query()
 
-		mode := defaultMode
-		if q.Get("mode") != "" {
-			mode, _ = strconv.Atoi(q.Get("mode"))
+		specs, err := indicators.ParseSpecs(q.Get("indicators"))
+		if err != nil {
+			apierror.Write(w, r, apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error()))
+			return
+		}
+
+		symbol, apiErr := symbolParam(q)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		mode, apiErr := modeParam(q, defaultMode)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
 		}
 
-		interval := defaultInterval
-		if q.Get("interval") != "" {
-			interval, _ = strconv.Atoi(q.Get("interval"))
+		interval, apiErr := intervalParam(q, defaultInterval)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
 		}
 
-		resp, err := s.stockGetter.Get(r.Context(), stockgetter.GetStockArgs{
-			Mode:     mode,
-			Interval: interval,
-			Symbol:   q.Get("symbol"),
+		limit, apiErr := limitParam(q)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		schema, apiErr := schemaParam(q, SchemaV1)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		adjusted, apiErr := boolParam(q, "adjusted")
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		asset, apiErr := assetParam(q, stockgetter.AssetStock)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		market, apiErr := currencyParam(q, "market")
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		currency, apiErr := currencyParam(q, "currency")
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		loc, apiErr := timeZoneParam(q)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		dates := dateLocation(mode, asset, symbol, loc)
+
+		from, apiErr := timeParam(q, "from", false, dates)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		to, apiErr := timeParam(q, "to", true, dates)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		resp, lines, err := s.getSeries(r.Context(), specs, stockgetter.GetStockArgs{
+			Mode:      mode,
+			Interval:  interval,
+			Symbol:    symbol,
+			Asset:     asset,
+			Market:    market,
+			From:      from,
+			To:        to,
+			Limit:     limit,
+			Cursor:    q.Get("cursor"),
+			Adjusted:  adjusted,
+			Currency:  currency,
+			MarketCap: schema == SchemaV2,
 		})
 		if err != nil {
 			log.Println("got error when getting stock data", err)
 
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("internal error"))
+			apierror.Write(w, r, getterError(err))
 			return
 		}
 
+		if loc != nil {
+			resp = stockgetter.InZone(resp, mode, loc)
+		}
+
 		pretty, _ := json.MarshalIndent(resp, "", "  ")
 		log.Printf("stock data \n%s", string(pretty))
 
-		text, err := json.Marshal(resp)
+		res := stockResponse{Stock: resp, Indicators: lines}
+
+		if schema == SchemaV1 {
+			s.respond(w, r, format, newStockResponseV1(res))
+			return
+		}
+
+		s.respond(w, r, format, res)
+	}
+}
+
+// getSeries gets the series of args along with the indicators of specs. The
+// indicators are computed over the whole series and cut to the range after,
+// so the first points of a range or a page don't go without them for lack of
+// history.
+func (s *Server) getSeries(ctx context.Context, specs []indicators.Spec, args stockgetter.GetStockArgs) (stockgetter.Stock, map[string][]indicators.Value, error) {
+	if len(specs) == 0 {
+		stock, err := s.stockGetter.Get(ctx, args)
+		return stock, nil, err
+	}
+
+	whole, err := s.stockGetter.Get(ctx, args.Whole(time.Now()))
+	if err != nil {
+		return stockgetter.Stock{}, nil, err
+	}
+
+	stock, err := stockgetter.ApplyRange(whole, args)
+	if err != nil {
+		return stockgetter.Stock{}, nil, err
+	}
+
+	return stock, indicators.Window(indicators.Compute(whole.Points, specs), stock.Points), nil
+}
+
+func (s *Server) HandleGetQuote() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format, apiErr := s.negotiate(w, r)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		symbol, apiErr := symbolParam(r.URL.Query())
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		resp, err := s.quoteGetter.GetQuote(r.Context(), symbol)
+		if err != nil {
+			log.Println("got error when getting quote data", err)
+
+			apierror.Write(w, r, getterError(err))
+			return
+		}
+
+		s.respond(w, r, format, quoteResponse(resp))
+	}
+}
+
+// HandleGetFundamentals returns the annual and quarterly reports of the
+// statement parameter.
+func (s *Server) HandleGetFundamentals() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format, apiErr := s.negotiate(w, r)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		q := r.URL.Query()
+
+		symbol, apiErr := symbolParam(q)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		statement, apiErr := statementParam(q)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		resp, err := s.fundamentalsGetter.GetFundamentals(r.Context(), symbol, statement)
+		if err != nil {
+			log.Println("got error when getting fundamentals data", err)
+
+			apierror.Write(w, r, getterError(err))
+			return
+		}
+
+		s.respond(w, r, format, fundamentalsResponse(resp))
+	}
+}
+
+// HandleGetIndicator returns an indicator series as alphavantage computes it,
+// for parity with reports built on its numbers rather than ours.
+func (s *Server) HandleGetIndicator() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format, apiErr := s.negotiate(w, r)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		q := r.URL.Query()
+
+		symbol, apiErr := symbolParam(q)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		fn, timePeriod, seriesType, apiErr := indicatorParams(q)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		mode, apiErr := modeParam(q, stockgetter.TimeModeDaily)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		interval, apiErr := intervalParam(q, stockgetter.DefaultInterval)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		resp, err := s.indicatorGetter.GetIndicator(r.Context(), stockgetter.IndicatorArgs{
+			Function:   fn.Name,
+			Symbol:     symbol,
+			Mode:       mode,
+			Interval:   interval,
+			TimePeriod: timePeriod,
+			SeriesType: seriesType,
+		})
 		if err != nil {
-			log.Println("got error when marshalling data", err)
+			log.Println("got error when getting indicator data", err)
 
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("internal error"))
+			apierror.Write(w, r, getterError(err))
 			return
 		}
 
-		encrypted, err := s.encService.Encrypt(r.Context(), text)
+		s.respond(w, r, format, indicatorResponse(resp))
+	}
+}
+
+// HandleSearchSymbols returns the symbols matching the q parameter ranked by
+// match score.
+func (s *Server) HandleSearchSymbols() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		format, apiErr := s.negotiate(w, r)
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		keywords, apiErr := requiredParam(r.URL.Query(), "q")
+		if apiErr != nil {
+			apierror.Write(w, r, apiErr)
+			return
+		}
+
+		resp, err := s.searcher.SearchSymbols(r.Context(), keywords)
 		if err != nil {
-			log.Println("got error when encrypting data", err)
+			log.Println("got error when searching symbols", err)
 
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("internal error"))
+			apierror.Write(w, r, getterError(err))
 			return
 		}
 
+		s.respond(w, r, format, searchResponse(resp))
+	}
+}
+
+// HandleGetBudget reports the upstream calls left before the rate limit kicks
+// in. It carries no market data so it is served in plain json.
+func (s *Server) HandleGetBudget() http.HandlerFunc {
+	type response struct {
+		Limited         bool `json:"limited"`
+		MinuteRemaining int  `json:"minute_remaining"`
+		DayRemaining    int  `json:"day_remaining"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		budget, limited := s.budget.RemainingBudget()
+
+		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
-		w.Write(encrypted)
+		json.NewEncoder(w).Encode(response{
+			Limited:         limited,
+			MinuteRemaining: budget.MinuteRemaining,
+			DayRemaining:    budget.DayRemaining,
+		})
+	}
+}
+
+// getterError maps the failures a client can act on to their own status,
+// anything else is an internal error.
+func getterError(err error) *apierror.Error {
+	switch {
+	case errors.Is(err, stockgetter.ErrInvalidRange), errors.Is(err, stockgetter.ErrInvalidCursor):
+		return apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+	case errors.Is(err, stockgetter.ErrUnsupportedCurrency):
+		return apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter, "symbol can't be priced in this currency")
+	case errors.Is(err, alphavantage.ErrRateLimited), errors.Is(err, alphavantage.ErrRateLimitExceeded):
+		return apierror.NewRetryable(http.StatusTooManyRequests, apierror.CodeRateLimited, "rate limited, try again later")
+	case errors.Is(err, alphavantage.ErrInvalidSymbol), errors.Is(err, alphavantage.ErrEmptyQuote),
+		errors.Is(err, alphavantage.ErrEmptyFundamentals):
+		return apierror.New(http.StatusNotFound, apierror.CodeSymbolNotFound, "symbol not found")
+	case errors.Is(err, alphavantage.ErrPremiumEndpoint):
+		return apierror.New(http.StatusPaymentRequired, apierror.CodePremiumRequired, "premium data not available")
+	case errors.Is(err, alphavantage.ErrServerResponse):
+		return apierror.NewRetryable(http.StatusBadGateway, apierror.CodeUpstreamError, "upstream provider failed")
+	case errors.Is(err, context.DeadlineExceeded):
+		return apierror.NewRetryable(http.StatusGatewayTimeout, apierror.CodeUpstreamTimeout, "upstream provider timed out")
 	}
+
+	return apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "internal error")
+}
+
+// encryptError maps a failure to encrypt a response, none of them are the
+// caller's fault.
+func encryptError(err error) *apierror.Error {
+	switch {
+	case errors.Is(err, client.ErrUnavailable):
+		return apierror.NewRetryable(http.StatusServiceUnavailable, apierror.CodeEncryptorUnavailable, "encryptor unavailable")
+	case errors.Is(err, client.ErrServerError):
+		return apierror.NewRetryable(http.StatusBadGateway, apierror.CodeEncryptorError, "encryptor failed")
+	case errors.Is(err, context.DeadlineExceeded):
+		return apierror.NewRetryable(http.StatusGatewayTimeout, apierror.CodeEncryptorUnavailable, "encryptor timed out")
+	}
+
+	return apierror.New(http.StatusInternalServerError, apierror.CodeInternal, "internal error")
 }
@@ -7,8 +7,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"stockplay/internal/apps/encryptor/pkg/client"
 	"stockplay/internal/apps/stocks/pkg/stockgetter"
+	"stockplay/internal/pkg/apierror"
+	"stockplay/pkg/alphavantage"
 )
 
 type failStockGetter int
@@ -17,18 +21,116 @@ func (f failStockGetter) Get(ctx context.Context, args stockgetter.GetStockArgs)
 	return stockgetter.Stock{}, errors.New("any err")
 }
 
+type errStockGetter struct {
+	err error
+}
+
+func (e errStockGetter) Get(ctx context.Context, args stockgetter.GetStockArgs) (stockgetter.Stock, error) {
+	return stockgetter.Stock{}, fmt.Errorf("failed to get stock: %w", e.err)
+}
+
 type successStockGetter int
 
 func (s successStockGetter) Get(ctx context.Context, args stockgetter.GetStockArgs) (stockgetter.Stock, error) {
 	return stockgetter.Stock{}, nil
 }
 
+type failQuoteGetter int
+
+func (f failQuoteGetter) GetQuote(ctx context.Context, symbol string) (stockgetter.Quote, error) {
+	return stockgetter.Quote{}, errors.New("any err")
+}
+
+type successQuoteGetter int
+
+func (s successQuoteGetter) GetQuote(ctx context.Context, symbol string) (stockgetter.Quote, error) {
+	return stockgetter.Quote{Symbol: symbol}, nil
+}
+
+type errFundamentalsGetter struct {
+	err error
+}
+
+func (e errFundamentalsGetter) GetFundamentals(ctx context.Context, symbol string, statement stockgetter.Statement) (stockgetter.Fundamentals, error) {
+	return stockgetter.Fundamentals{}, fmt.Errorf("failed to get fundamentals: %w", e.err)
+}
+
+type successFundamentalsGetter int
+
+func (s successFundamentalsGetter) GetFundamentals(ctx context.Context, symbol string, statement stockgetter.Statement) (stockgetter.Fundamentals, error) {
+	return stockgetter.Fundamentals{
+		Symbol:    symbol,
+		Statement: statement,
+		Annual: []stockgetter.Report{
+			{Period: stockgetter.PeriodAnnual, FiscalDateEnding: 1, Items: map[string]float64{"reported_eps": 1.5}},
+		},
+	}, nil
+}
+
+// recordingIndicatorGetter answers with an empty series of the function it
+// was asked for.
+type recordingIndicatorGetter struct {
+	args stockgetter.IndicatorArgs
+}
+
+func (r *recordingIndicatorGetter) GetIndicator(ctx context.Context, args stockgetter.IndicatorArgs) (stockgetter.IndicatorSeries, error) {
+	r.args = args
+	return stockgetter.IndicatorSeries{Symbol: args.Symbol, Function: args.Function, Columns: []string{"SMA"}}, nil
+}
+
+type failSymbolSearcher int
+
+func (f failSymbolSearcher) SearchSymbols(ctx context.Context, keywords string) ([]stockgetter.SymbolMatch, error) {
+	return nil, errors.New("any err")
+}
+
+type successSymbolSearcher int
+
+func (s successSymbolSearcher) SearchSymbols(ctx context.Context, keywords string) ([]stockgetter.SymbolMatch, error) {
+	return []stockgetter.SymbolMatch{{Symbol: keywords}}, nil
+}
+
+type fixedBudget struct {
+	budget  alphavantage.Budget
+	limited bool
+}
+
+func (f fixedBudget) RemainingBudget() (alphavantage.Budget, bool) {
+	return f.budget, f.limited
+}
+
+type pointsStockGetter int
+
+func (p pointsStockGetter) Get(ctx context.Context, args stockgetter.GetStockArgs) (stockgetter.Stock, error) {
+	return stockgetter.Stock{
+		Points: []stockgetter.Point{
+			{Close: 1, Time: 1},
+			{Close: 3, Time: 2},
+		},
+	}, nil
+}
+
+// echoEncSvc leaves the text as it is so tests can look at the response.
+type echoEncSvc int
+
+func (e echoEncSvc) Encrypt(ctx context.Context, text []byte) ([]byte, error) {
+	return text, nil
+}
+
 type failEncSvc int
 
 func (f failEncSvc) Encrypt(ctx context.Context, text []byte) ([]byte, error) {
 	return nil, errors.New("any err")
 }
 
+type errEncSvc struct {
+	err error
+}
+
+func (e errEncSvc) Encrypt(ctx context.Context, text []byte) ([]byte, error) {
+	return nil, e.err
+}
+
 type successEncSvc int
 
 func (s successEncSvc) Encrypt(ctx context.Context, text []byte) ([]byte, error) {
@@ -49,7 +151,55 @@ func TestServer_HandleGetStock(t *testing.T) {
 			enc:                failEncSvc(1),
 			sg:                 failStockGetter(1),
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedBody:       "internal error",
+			expectedBody:       `{"code":"internal_error","message":"internal error","retryable":false}` + "\n",
+			symbol:             "abcd123",
+		},
+		{
+			caseName:           "when rate limited upstream",
+			enc:                successEncSvc(1),
+			sg:                 errStockGetter{err: alphavantage.ErrRateLimited},
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedBody:       `{"code":"rate_limited","message":"rate limited, try again later","retryable":true}` + "\n",
+			symbol:             "abcd123",
+		},
+		{
+			caseName:           "when out of client budget",
+			enc:                successEncSvc(1),
+			sg:                 errStockGetter{err: alphavantage.ErrRateLimitExceeded},
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedBody:       `{"code":"rate_limited","message":"rate limited, try again later","retryable":true}` + "\n",
+			symbol:             "abcd123",
+		},
+		{
+			caseName:           "when symbol is invalid",
+			enc:                successEncSvc(1),
+			sg:                 errStockGetter{err: alphavantage.ErrInvalidSymbol},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"code":"symbol_not_found","message":"symbol not found","retryable":false}` + "\n",
+			symbol:             "abcd123",
+		},
+		{
+			caseName:           "when currency is not offered",
+			enc:                successEncSvc(1),
+			sg:                 errStockGetter{err: fmt.Errorf("%w: USD to XYZ", stockgetter.ErrUnsupportedCurrency)},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"symbol can't be priced in this currency","retryable":false}` + "\n",
+			symbol:             "abcd123",
+		},
+		{
+			caseName:           "when endpoint is premium",
+			enc:                successEncSvc(1),
+			sg:                 errStockGetter{err: alphavantage.ErrPremiumEndpoint},
+			expectedStatusCode: http.StatusPaymentRequired,
+			expectedBody:       `{"code":"premium_required","message":"premium data not available","retryable":false}` + "\n",
+			symbol:             "abcd123",
+		},
+		{
+			caseName:           "when upstream fails",
+			enc:                successEncSvc(1),
+			sg:                 errStockGetter{err: fmt.Errorf("status 503: %w", alphavantage.ErrServerResponse)},
+			expectedStatusCode: http.StatusBadGateway,
+			expectedBody:       `{"code":"upstream_error","message":"upstream provider failed","retryable":true}` + "\n",
 			symbol:             "abcd123",
 		},
 		{
@@ -57,7 +207,15 @@ func TestServer_HandleGetStock(t *testing.T) {
 			enc:                failEncSvc(1),
 			sg:                 successStockGetter(1),
 			expectedStatusCode: http.StatusInternalServerError,
-			expectedBody:       "internal error",
+			expectedBody:       `{"code":"internal_error","message":"internal error","retryable":false}` + "\n",
+			symbol:             "abcd123",
+		},
+		{
+			caseName:           "when encryptor is unavailable",
+			enc:                errEncSvc{err: fmt.Errorf("dial tcp: %w", client.ErrUnavailable)},
+			sg:                 successStockGetter(1),
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       `{"code":"encryptor_unavailable","message":"encryptor unavailable","retryable":true}` + "\n",
 			symbol:             "abcd123",
 		},
 		{
@@ -79,7 +237,448 @@ func TestServer_HandleGetStock(t *testing.T) {
 			encService:  tt.enc,
 		}
 
-		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		req, err := http.NewRequest(http.MethodGet, "/?symbol="+tt.symbol, nil)
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+
+		rw := httptest.NewRecorder()
+
+		s.HandleGetStock().ServeHTTP(rw, req)
+
+		if rw.Code != tt.expectedStatusCode {
+			t.Errorf("%s status code [%d] not equal expected [%d]", logTestcase, rw.Code, tt.expectedStatusCode)
+		}
+
+		if rw.Body.String() != tt.expectedBody {
+			t.Errorf("%s body [%s] not equal expected [%s]", logTestcase, rw.Body.String(), tt.expectedBody)
+		}
+	}
+}
+
+// noOverviewAvClient serves a daily series and the empty overview
+// alphavantage sends for funds, any other call panics.
+type noOverviewAvClient struct {
+	stockgetter.AlphaVantageClient
+}
+
+func (n noOverviewAvClient) GetStockTimeSeries(ctx context.Context, args alphavantage.GetStockArgs) ([]alphavantage.Stock, error) {
+	return []alphavantage.Stock{{Open: 1, High: 1, Low: 1, Close: 1, Volume: 1, Date: time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC)}}, nil
+}
+
+func (n noOverviewAvClient) GetOverview(ctx context.Context, symbol string) (alphavantage.Overview, error) {
+	return alphavantage.Overview{}, fmt.Errorf("overview of %s: %w", symbol, alphavantage.ErrEmptyOverview)
+}
+
+func TestServer_HandleGetStockWithoutOverview(t *testing.T) {
+	s := NewServer(stockgetter.NewAlphaVantageStockGetter(noOverviewAvClient{}), successEncSvc(1))
+
+	req, err := http.NewRequest(http.MethodGet, "/?symbol=SPY&mode=daily&currency=EUR", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rw := httptest.NewRecorder()
+
+	s.HandleGetStock().ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusBadRequest {
+		t.Errorf("status code [%d] not equal expected [%d]", rw.Code, http.StatusBadRequest)
+	}
+
+	expectedBody := `{"code":"invalid_parameter","message":"symbol can't be priced in this currency","retryable":false}` + "\n"
+	if rw.Body.String() != expectedBody {
+		t.Errorf("body [%s] not equal expected [%s]", rw.Body.String(), expectedBody)
+	}
+
+	// the series is still served without a market cap when no currency is asked
+	req, err = http.NewRequest(http.MethodGet, "/?symbol=SPY&mode=daily&schema=2", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rw = httptest.NewRecorder()
+
+	s.HandleGetStock().ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusOK {
+		t.Errorf("status code [%d] not equal expected [%d]", rw.Code, http.StatusOK)
+	}
+}
+
+func TestServer_HandleGetQuote(t *testing.T) {
+	var tts = []struct {
+		caseName           string
+		enc                EncryptService
+		qg                 QuoteGetter
+		expectedStatusCode int
+		expectedBody       string
+		symbol             string
+	}{
+		{
+			caseName:           "when symbol is missing",
+			enc:                successEncSvc(1),
+			qg:                 successQuoteGetter(1),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"missing_parameter","message":"symbol is required","request_id":"abcd","retryable":false}` + "\n",
+			symbol:             "",
+		},
+		{
+			caseName:           "when error getting the quote",
+			enc:                successEncSvc(1),
+			qg:                 failQuoteGetter(1),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"code":"internal_error","message":"internal error","request_id":"abcd","retryable":false}` + "\n",
+			symbol:             "abcd123",
+		},
+		{
+			caseName:           "when error encrypting data",
+			enc:                failEncSvc(1),
+			qg:                 successQuoteGetter(1),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"code":"internal_error","message":"internal error","request_id":"abcd","retryable":false}` + "\n",
+			symbol:             "abcd123",
+		},
+		{
+			caseName:           "when success",
+			enc:                successEncSvc(1),
+			qg:                 successQuoteGetter(1),
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			symbol:             "abcd123",
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		s := NewServer(successStockGetter(1), tt.enc, WithQuoteGetter(tt.qg))
+
+		req, err := http.NewRequest(http.MethodGet, "/quote?symbol="+tt.symbol, nil)
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+
+		req.Header.Set(apierror.RequestIDHeader, "abcd")
+
+		rw := httptest.NewRecorder()
+
+		s.Router().ServeHTTP(rw, req)
+
+		if rw.Code != tt.expectedStatusCode {
+			t.Errorf("%s status code [%d] not equal expected [%d]", logTestcase, rw.Code, tt.expectedStatusCode)
+		}
+
+		if rw.Body.String() != tt.expectedBody {
+			t.Errorf("%s body [%s] not equal expected [%s]", logTestcase, rw.Body.String(), tt.expectedBody)
+		}
+	}
+}
+
+func TestServer_HandleSearchSymbols(t *testing.T) {
+	var tts = []struct {
+		caseName           string
+		enc                EncryptService
+		searcher           SymbolSearcher
+		expectedStatusCode int
+		expectedBody       string
+		keywords           string
+	}{
+		{
+			caseName:           "when keywords are missing",
+			enc:                successEncSvc(1),
+			searcher:           successSymbolSearcher(1),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"missing_parameter","message":"q is required","request_id":"abcd","retryable":false}` + "\n",
+			keywords:           "",
+		},
+		{
+			caseName:           "when error searching",
+			enc:                successEncSvc(1),
+			searcher:           failSymbolSearcher(1),
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedBody:       `{"code":"internal_error","message":"internal error","request_id":"abcd","retryable":false}` + "\n",
+			keywords:           "ibm",
+		},
+		{
+			caseName:           "when success",
+			enc:                successEncSvc(1),
+			searcher:           successSymbolSearcher(1),
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			keywords:           "ibm",
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		s := NewServer(successStockGetter(1), tt.enc, WithSymbolSearcher(tt.searcher))
+
+		req, err := http.NewRequest(http.MethodGet, "/search?q="+tt.keywords, nil)
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+
+		req.Header.Set(apierror.RequestIDHeader, "abcd")
+
+		rw := httptest.NewRecorder()
+
+		s.Router().ServeHTTP(rw, req)
+
+		if rw.Code != tt.expectedStatusCode {
+			t.Errorf("%s status code [%d] not equal expected [%d]", logTestcase, rw.Code, tt.expectedStatusCode)
+		}
+
+		if rw.Body.String() != tt.expectedBody {
+			t.Errorf("%s body [%s] not equal expected [%s]", logTestcase, rw.Body.String(), tt.expectedBody)
+		}
+	}
+}
+
+func TestServer_HandleGetFundamentals(t *testing.T) {
+	var tts = []struct {
+		caseName           string
+		enc                EncryptService
+		fg                 FundamentalsGetter
+		expectedStatusCode int
+		expectedBody       string
+		query              string
+	}{
+		{
+			caseName:           "when statement is missing",
+			enc:                echoEncSvc(1),
+			fg:                 successFundamentalsGetter(1),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"missing_parameter","message":"statement is required","request_id":"abcd","retryable":false}` + "\n",
+			query:              "symbol=IBM",
+		},
+		{
+			caseName:           "when statement is unknown",
+			enc:                echoEncSvc(1),
+			fg:                 successFundamentalsGetter(1),
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"statement must be one of income, balance, cashflow, earnings","request_id":"abcd","retryable":false}` + "\n",
+			query:              "symbol=IBM&statement=dividends",
+		},
+		{
+			caseName:           "when symbol has no fundamentals",
+			enc:                echoEncSvc(1),
+			fg:                 errFundamentalsGetter{err: alphavantage.ErrEmptyFundamentals},
+			expectedStatusCode: http.StatusNotFound,
+			expectedBody:       `{"code":"symbol_not_found","message":"symbol not found","request_id":"abcd","retryable":false}` + "\n",
+			query:              "symbol=SPY&statement=income",
+		},
+		{
+			caseName:           "when success",
+			enc:                echoEncSvc(1),
+			fg:                 successFundamentalsGetter(1),
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"symbol":"IBM","statement":"earnings","annual":[{"period":"annual","fiscal_date_ending":1,"items":{"reported_eps":1.5}}],"quarterly":null}`,
+			query:              "symbol=IBM&statement=Earnings",
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		s := NewServer(successStockGetter(1), tt.enc, WithFundamentalsGetter(tt.fg))
+
+		req, err := http.NewRequest(http.MethodGet, "/fundamentals?"+tt.query, nil)
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+
+		req.Header.Set(apierror.RequestIDHeader, "abcd")
+
+		rw := httptest.NewRecorder()
+
+		s.Router().ServeHTTP(rw, req)
+
+		if rw.Code != tt.expectedStatusCode {
+			t.Errorf("%s status code [%d] not equal expected [%d]", logTestcase, rw.Code, tt.expectedStatusCode)
+		}
+
+		if rw.Body.String() != tt.expectedBody {
+			t.Errorf("%s body [%s] not equal expected [%s]", logTestcase, rw.Body.String(), tt.expectedBody)
+		}
+	}
+}
+
+func TestServer_HandleGetIndicator(t *testing.T) {
+	var tts = []struct {
+		caseName           string
+		query              string
+		expectedStatusCode int
+		expectedBody       string
+		expectedArgs       stockgetter.IndicatorArgs
+	}{
+		{
+			caseName:           "when function is missing",
+			query:              "symbol=IBM",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"missing_parameter","message":"function is required","request_id":"abcd","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when function is unknown",
+			query:              "symbol=IBM&function=macd",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"function must be one of sma, ema, rsi, stoch, adx, cci, aroon, bbands, obv","request_id":"abcd","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when time period is missing",
+			query:              "symbol=IBM&function=sma",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"missing_parameter","message":"time_period is required","request_id":"abcd","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when series type is unknown",
+			query:              "symbol=IBM&function=sma&time_period=20&series_type=median",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"series_type must be one of close, open, high, low","request_id":"abcd","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when success",
+			query:              "symbol=IBM&function=SMA&time_period=20",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"symbol":"IBM","function":"sma","columns":["SMA"],"points":null}`,
+			expectedArgs: stockgetter.IndicatorArgs{
+				Function:   "sma",
+				Symbol:     "IBM",
+				Mode:       stockgetter.TimeModeDaily,
+				Interval:   stockgetter.DefaultInterval,
+				TimePeriod: 20,
+				SeriesType: "close",
+			},
+		},
+		{
+			caseName:           "when function takes no parameters",
+			query:              "symbol=IBM&function=obv&mode=intraday&interval=60min&time_period=abc",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"symbol":"IBM","function":"obv","columns":["SMA"],"points":null}`,
+			expectedArgs: stockgetter.IndicatorArgs{
+				Function: "obv",
+				Symbol:   "IBM",
+				Mode:     stockgetter.TimeModeIntraday,
+				Interval: stockgetter.TimeInterval60Min,
+			},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		ig := &recordingIndicatorGetter{}
+		s := NewServer(successStockGetter(1), echoEncSvc(1), WithIndicatorGetter(ig))
+
+		req, err := http.NewRequest(http.MethodGet, "/indicator?"+tt.query, nil)
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+
+		req.Header.Set(apierror.RequestIDHeader, "abcd")
+
+		rw := httptest.NewRecorder()
+
+		s.Router().ServeHTTP(rw, req)
+
+		if rw.Code != tt.expectedStatusCode {
+			t.Errorf("%s status code [%d] not equal expected [%d]", logTestcase, rw.Code, tt.expectedStatusCode)
+		}
+
+		if rw.Body.String() != tt.expectedBody {
+			t.Errorf("%s body [%s] not equal expected [%s]", logTestcase, rw.Body.String(), tt.expectedBody)
+		}
+
+		if ig.args != tt.expectedArgs {
+			t.Errorf("%s args %+v not equal expected %+v", logTestcase, ig.args, tt.expectedArgs)
+		}
+	}
+}
+
+func TestServer_HandleGetBudget(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		budget       BudgetReporter
+		expectedBody string
+	}{
+		{
+			caseName:     "when not limited",
+			budget:       fixedBudget{budget: alphavantage.Budget{MinuteRemaining: -1, DayRemaining: -1}},
+			expectedBody: `{"limited":false,"minute_remaining":-1,"day_remaining":-1}` + "\n",
+		},
+		{
+			caseName:     "when limited",
+			budget:       fixedBudget{budget: alphavantage.Budget{MinuteRemaining: 3, DayRemaining: 20}, limited: true},
+			expectedBody: `{"limited":true,"minute_remaining":3,"day_remaining":20}` + "\n",
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		s := NewServer(successStockGetter(1), successEncSvc(1), WithBudgetReporter(tt.budget))
+
+		req, err := http.NewRequest(http.MethodGet, "/budget", nil)
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+
+		req.Header.Set(apierror.RequestIDHeader, "abcd")
+
+		rw := httptest.NewRecorder()
+
+		s.Router().ServeHTTP(rw, req)
+
+		if rw.Code != http.StatusOK {
+			t.Errorf("%s status code [%d] not equal expected [%d]", logTestcase, rw.Code, http.StatusOK)
+		}
+
+		if rw.Body.String() != tt.expectedBody {
+			t.Errorf("%s body [%s] not equal expected [%s]", logTestcase, rw.Body.String(), tt.expectedBody)
+		}
+	}
+}
+
+func TestServer_HandleGetStockIndicators(t *testing.T) {
+	var tts = []struct {
+		caseName           string
+		indicators         string
+		expectedStatusCode int
+		expectedBody       string
+	}{
+		{
+			caseName:           "when indicator is unknown",
+			indicators:         "foo",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"foo: unknown indicator","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when no indicator is requested",
+			indicators:         "",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"points":[{"current_value":1,"bid":0,"ask":0,"variation":0,"previous_close":0,"open":0,"volume":0,"time":1},{"current_value":3,"bid":0,"ask":0,"variation":0,"previous_close":0,"open":0,"volume":0,"time":2}],"market_cap":0,"avg_volume":0}`,
+		},
+		{
+			caseName:           "when success",
+			indicators:         "sma:2",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `{"points":[{"current_value":1,"bid":0,"ask":0,"variation":0,"previous_close":0,"open":0,"volume":0,"time":1},{"current_value":3,"bid":0,"ask":0,"variation":0,"previous_close":0,"open":0,"volume":0,"time":2}],"market_cap":0,"avg_volume":0,"indicators":{"sma_2":[{"time":2,"value":2}]}}`,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		s := NewServer(pointsStockGetter(1), echoEncSvc(1))
+
+		req, err := http.NewRequest(http.MethodGet, "/?symbol=abcd123&indicators="+tt.indicators, nil)
 		if err != nil {
 			t.Error(logTestcase, err)
 		}
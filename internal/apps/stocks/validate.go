@@ -0,0 +1,326 @@
+package stocks
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"stockplay/internal/apps/stocks/pkg/stockgetter"
+	"stockplay/internal/pkg/apierror"
+	"stockplay/pkg/alphavantage"
+)
+
+// symbolPattern covers listed tickers such as IBM, BRK.B, RDS-A and the
+// exchange suffixed TSCO.LON.
+var symbolPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.\-]{0,19}$`)
+
+// currencyPattern is an ISO 4217 code such as EUR.
+var currencyPattern = regexp.MustCompile(`^[A-Za-z]{3}$`)
+
+// maxLimit bounds a page, it is about 20 years of daily points.
+const maxLimit = 5000
+
+// maxTimePeriod bounds the points an indicator is computed over.
+const maxTimePeriod = 1000
+
+const layoutDate = "2006-01-02"
+
+// requiredParam returns the trimmed value of name, which must be set.
+func requiredParam(q url.Values, name string) (string, *apierror.Error) {
+	v := strings.TrimSpace(q.Get(name))
+	if v == "" {
+		return "", apierror.New(http.StatusBadRequest, apierror.CodeMissingParameter, name+" is required")
+	}
+
+	return v, nil
+}
+
+// symbolParam returns the symbol parameter, which must be set and look like a
+// ticker.
+func symbolParam(q url.Values) (string, *apierror.Error) {
+	symbol, apiErr := requiredParam(q, "symbol")
+	if apiErr != nil {
+		return "", apiErr
+	}
+
+	if !symbolPattern.MatchString(symbol) {
+		return "", apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter,
+			"symbol must be 1 to 20 letters, digits, dots or dashes")
+	}
+
+	return symbol, nil
+}
+
+// currencyParam returns the name parameter as an upper-cased currency code,
+// empty when it is left out.
+func currencyParam(q url.Values, name string) (string, *apierror.Error) {
+	v := strings.TrimSpace(q.Get(name))
+	if v == "" {
+		return "", nil
+	}
+
+	if !currencyPattern.MatchString(v) {
+		return "", apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter,
+			name+" must be a 3 letter currency code")
+	}
+
+	return strings.ToUpper(v), nil
+}
+
+// assetParam returns the asset parameter, def when it is left out.
+func assetParam(q url.Values, def stockgetter.Asset) (stockgetter.Asset, *apierror.Error) {
+	v := strings.TrimSpace(q.Get("asset"))
+	if v == "" {
+		return def, nil
+	}
+
+	asset := stockgetter.Asset(strings.ToLower(v))
+	if !asset.Valid() {
+		allowed := make([]string, 0, len(stockgetter.Assets()))
+		for _, a := range stockgetter.Assets() {
+			allowed = append(allowed, string(a))
+		}
+
+		return "", invalidChoice("asset", allowed)
+	}
+
+	return asset, nil
+}
+
+// indicatorParams returns the function parameter and the parameters that
+// function needs.
+func indicatorParams(q url.Values) (fn stockgetter.IndicatorFunction, timePeriod int, seriesType string, apiErr *apierror.Error) {
+	name, apiErr := requiredParam(q, "function")
+	if apiErr != nil {
+		return fn, 0, "", apiErr
+	}
+
+	fn, ok := stockgetter.LookupIndicator(name)
+	if !ok {
+		allowed := make([]string, 0, len(stockgetter.IndicatorFunctions()))
+		for _, f := range stockgetter.IndicatorFunctions() {
+			allowed = append(allowed, f.Name)
+		}
+
+		return fn, 0, "", invalidChoice("function", allowed)
+	}
+
+	if fn.TimePeriod {
+		v, apiErr := requiredParam(q, "time_period")
+		if apiErr != nil {
+			return fn, 0, "", apiErr
+		}
+
+		var err error
+		timePeriod, err = strconv.Atoi(v)
+		if err != nil || timePeriod < 1 || timePeriod > maxTimePeriod {
+			return fn, 0, "", apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter,
+				fmt.Sprintf("time_period must be a number from 1 to %d", maxTimePeriod))
+		}
+	}
+
+	if fn.SeriesType {
+		seriesType = strings.ToLower(strings.TrimSpace(q.Get("series_type")))
+		if seriesType == "" {
+			seriesType = "close"
+		}
+
+		valid := false
+		for _, t := range stockgetter.SeriesTypes() {
+			valid = valid || t == seriesType
+		}
+
+		if !valid {
+			return fn, 0, "", invalidChoice("series_type", stockgetter.SeriesTypes())
+		}
+	}
+
+	return fn, timePeriod, seriesType, nil
+}
+
+// modeParam returns the mode parameter picked by name or number, def when it
+// is left out.
+func modeParam(q url.Values, def stockgetter.Mode) (stockgetter.Mode, *apierror.Error) {
+	v := strings.TrimSpace(q.Get("mode"))
+	if v == "" {
+		return def, nil
+	}
+
+	mode, err := stockgetter.ParseMode(v)
+	if err != nil {
+		allowed := make([]string, 0, len(stockgetter.Modes()))
+		for _, m := range stockgetter.Modes() {
+			allowed = append(allowed, fmt.Sprintf("%s (%d)", m, m))
+		}
+
+		return 0, invalidChoice("mode", allowed)
+	}
+
+	return mode, nil
+}
+
+// intervalParam returns the interval parameter picked by name or number, def
+// when it is left out.
+func intervalParam(q url.Values, def stockgetter.Interval) (stockgetter.Interval, *apierror.Error) {
+	v := strings.TrimSpace(q.Get("interval"))
+	if v == "" {
+		return def, nil
+	}
+
+	interval, err := stockgetter.ParseInterval(v)
+	if err != nil {
+		allowed := make([]string, 0, len(stockgetter.Intervals()))
+		for _, i := range stockgetter.Intervals() {
+			allowed = append(allowed, fmt.Sprintf("%s (%d)", i, i))
+		}
+
+		return 0, invalidChoice("interval", allowed)
+	}
+
+	return interval, nil
+}
+
+// statementParam returns the required statement parameter.
+func statementParam(q url.Values) (stockgetter.Statement, *apierror.Error) {
+	v, apiErr := requiredParam(q, "statement")
+	if apiErr != nil {
+		return "", apiErr
+	}
+
+	statement := stockgetter.Statement(strings.ToLower(v))
+	if !statement.Valid() {
+		allowed := make([]string, 0, len(stockgetter.Statements()))
+		for _, s := range stockgetter.Statements() {
+			allowed = append(allowed, string(s))
+		}
+
+		return "", invalidChoice("statement", allowed)
+	}
+
+	return statement, nil
+}
+
+func invalidChoice(name string, allowed []string) *apierror.Error {
+	return apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter,
+		fmt.Sprintf("%s must be one of %s", name, strings.Join(allowed, ", ")))
+}
+
+// timeParam returns the name parameter given as a date or an RFC 3339 time,
+// zero when it is left out. A date is read in loc and stands for its start, or
+// for its end when endOfDay is set so that a date only range includes its last
+// day.
+func timeParam(q url.Values, name string, endOfDay bool, loc *time.Location) (time.Time, *apierror.Error) {
+	v := strings.TrimSpace(q.Get(name))
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation(layoutDate, v, loc)
+	if err != nil {
+		return time.Time{}, apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter,
+			name+" must be a date (2006-01-02) or an RFC 3339 time")
+	}
+
+	if endOfDay {
+		// a day is not 24 hours long when the clocks change
+		t = t.AddDate(0, 0, 1).Add(-time.Second)
+	}
+
+	return t.UTC(), nil
+}
+
+// dateLocation returns the zone a date only range is read in. Intraday days
+// are those of tz when it is given, or else of the zone the series is stamped
+// in, the exchange's for stocks and UTC for crypto. Longer modes keep their
+// days at midnight UTC.
+func dateLocation(mode stockgetter.Mode, asset stockgetter.Asset, symbol string, tz *time.Location) *time.Location {
+	if mode != stockgetter.TimeModeIntraday {
+		return time.UTC
+	}
+
+	if tz != nil {
+		return tz
+	}
+
+	if asset == stockgetter.AssetCrypto {
+		return time.UTC
+	}
+
+	exchange, err := time.LoadLocation(alphavantage.SymbolTimeZone(symbol))
+	if err != nil {
+		return time.UTC
+	}
+
+	return exchange
+}
+
+// timeZoneParam returns the tz parameter as a location, nil when it is left
+// out. Local is refused since it is the server's zone, not one the caller
+// knows.
+func timeZoneParam(q url.Values) (*time.Location, *apierror.Error) {
+	v := strings.TrimSpace(q.Get("tz"))
+	if v == "" {
+		return nil, nil
+	}
+
+	loc, err := time.LoadLocation(v)
+	if err != nil || v == "Local" {
+		return nil, apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter,
+			"tz must be an IANA time zone such as America/New_York")
+	}
+
+	return loc, nil
+}
+
+// limitParam returns the limit parameter, zero when it is left out.
+func limitParam(q url.Values) (int, *apierror.Error) {
+	v := strings.TrimSpace(q.Get("limit"))
+	if v == "" {
+		return 0, nil
+	}
+
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit < 1 || limit > maxLimit {
+		return 0, apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter,
+			fmt.Sprintf("limit must be a number from 1 to %d", maxLimit))
+	}
+
+	return limit, nil
+}
+
+// boolParam returns the name parameter, false when it is left out.
+func boolParam(q url.Values, name string) (bool, *apierror.Error) {
+	v := strings.TrimSpace(q.Get(name))
+	if v == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter, name+" must be true or false")
+	}
+
+	return b, nil
+}
+
+// schemaParam returns the schema parameter, def when it is left out.
+func schemaParam(q url.Values, def int) (int, *apierror.Error) {
+	switch strings.TrimSpace(q.Get("schema")) {
+	case "":
+		return def, nil
+	case "1":
+		return SchemaV1, nil
+	case "2":
+		return SchemaV2, nil
+	}
+
+	return 0, invalidChoice("schema", []string{"1", "2"})
+}
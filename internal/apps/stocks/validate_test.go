@@ -0,0 +1,284 @@
+package stocks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"stockplay/internal/apps/stocks/pkg/stockgetter"
+)
+
+type recordingStockGetter struct {
+	args stockgetter.GetStockArgs
+}
+
+func (r *recordingStockGetter) Get(ctx context.Context, args stockgetter.GetStockArgs) (stockgetter.Stock, error) {
+	r.args = args
+	return stockgetter.Stock{}, nil
+}
+
+func TestServer_HandleGetStockValidation(t *testing.T) {
+	var tts = []struct {
+		caseName           string
+		query              string
+		expectedStatusCode int
+		expectedBody       string
+		expectedArgs       stockgetter.GetStockArgs
+	}{
+		{
+			caseName:           "when symbol is missing",
+			query:              "mode=daily",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"missing_parameter","message":"symbol is required","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when symbol is malformed",
+			query:              "symbol=IBM%3Bfunction%3DOVERVIEW",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"symbol must be 1 to 20 letters, digits, dots or dashes","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when mode is unknown",
+			query:              "symbol=IBM&mode=abc",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"mode must be one of intraday (0), daily (1), weekly (2), monthly (3)","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when interval is unknown",
+			query:              "symbol=IBM&mode=intraday&interval=2min",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"interval must be one of 1min (54), 5min (55), 15min (56), 30min (57), 60min (58)","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when mode and interval are named",
+			query:              "symbol=BRK.B&mode=Intraday&interval=15min",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			expectedArgs: stockgetter.GetStockArgs{
+				Symbol:   "BRK.B",
+				Asset:    stockgetter.AssetStock,
+				Mode:     stockgetter.TimeModeIntraday,
+				Interval: stockgetter.TimeInterval15Min,
+			},
+		},
+		{
+			caseName:           "when interval is left out",
+			query:              "symbol=IBM&mode=intraday",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			expectedArgs: stockgetter.GetStockArgs{
+				Symbol:   "IBM",
+				Asset:    stockgetter.AssetStock,
+				Mode:     stockgetter.TimeModeIntraday,
+				Interval: stockgetter.TimeInterval5Min,
+			},
+		},
+		{
+			caseName:           "when limit is out of bounds",
+			query:              "symbol=IBM&limit=0",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"limit must be a number from 1 to 5000","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when from is malformed",
+			query:              "symbol=IBM&from=yesterday",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"from must be a date (2006-01-02) or an RFC 3339 time","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when range and page are set",
+			query:              "symbol=IBM&mode=daily&from=2020-11-02&to=2020-11-06T12:00:00Z&limit=10&cursor=abc",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			expectedArgs: stockgetter.GetStockArgs{
+				Symbol:   "IBM",
+				Asset:    stockgetter.AssetStock,
+				Mode:     stockgetter.TimeModeDaily,
+				Interval: stockgetter.TimeInterval5Min,
+				From:     time.Date(2020, 11, 2, 0, 0, 0, 0, time.UTC),
+				To:       time.Date(2020, 11, 6, 12, 0, 0, 0, time.UTC),
+				Limit:    10,
+				Cursor:   "abc",
+			},
+		},
+		{
+			caseName:           "when to is a date",
+			query:              "symbol=IBM&mode=daily&to=2020-11-06",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			expectedArgs: stockgetter.GetStockArgs{
+				Symbol:   "IBM",
+				Asset:    stockgetter.AssetStock,
+				Mode:     stockgetter.TimeModeDaily,
+				Interval: stockgetter.TimeInterval5Min,
+				To:       time.Date(2020, 11, 6, 23, 59, 59, 0, time.UTC),
+			},
+		},
+		{
+			caseName:           "when schema 2 is asked",
+			query:              "symbol=IBM&schema=2",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			expectedArgs: stockgetter.GetStockArgs{
+				Symbol:    "IBM",
+				Asset:     stockgetter.AssetStock,
+				Mode:      stockgetter.TimeModeWeekly,
+				Interval:  stockgetter.TimeInterval5Min,
+				MarketCap: true,
+			},
+		},
+		{
+			caseName:           "when an intraday range is dates",
+			query:              "symbol=IBM&mode=intraday&from=2020-11-06&to=2020-11-06",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			expectedArgs: stockgetter.GetStockArgs{
+				Symbol:   "IBM",
+				Asset:    stockgetter.AssetStock,
+				Mode:     stockgetter.TimeModeIntraday,
+				Interval: stockgetter.TimeInterval5Min,
+				From:     time.Date(2020, 11, 6, 5, 0, 0, 0, time.UTC),
+				To:       time.Date(2020, 11, 7, 4, 59, 59, 0, time.UTC),
+			},
+		},
+		{
+			caseName:           "when an intraday range is dates in a zone",
+			query:              "symbol=TSCO.LON&mode=intraday&to=2020-11-06&tz=Asia/Tokyo",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			expectedArgs: stockgetter.GetStockArgs{
+				Symbol:   "TSCO.LON",
+				Asset:    stockgetter.AssetStock,
+				Mode:     stockgetter.TimeModeIntraday,
+				Interval: stockgetter.TimeInterval5Min,
+				To:       time.Date(2020, 11, 6, 14, 59, 59, 0, time.UTC),
+			},
+		},
+		{
+			caseName:           "when adjusted is malformed",
+			query:              "symbol=IBM&adjusted=maybe",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"adjusted must be true or false","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when adjusted is asked",
+			query:              "symbol=IBM&mode=monthly&adjusted=true",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			expectedArgs: stockgetter.GetStockArgs{
+				Symbol:   "IBM",
+				Asset:    stockgetter.AssetStock,
+				Mode:     stockgetter.TimeModeMonthly,
+				Interval: stockgetter.TimeInterval5Min,
+				Adjusted: true,
+			},
+		},
+		{
+			caseName:           "when currency is malformed",
+			query:              "symbol=IBM&currency=euro",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"currency must be a 3 letter currency code","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when currency is asked",
+			query:              "symbol=IBM&currency=eur",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			expectedArgs: stockgetter.GetStockArgs{
+				Symbol:   "IBM",
+				Asset:    stockgetter.AssetStock,
+				Mode:     stockgetter.TimeModeWeekly,
+				Interval: stockgetter.TimeInterval5Min,
+				Currency: "EUR",
+			},
+		},
+		{
+			caseName:           "when tz is unknown",
+			query:              "symbol=IBM&tz=Mars/Olympus",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"tz must be an IANA time zone such as America/New_York","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when tz is the server's",
+			query:              "symbol=IBM&tz=Local",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"tz must be an IANA time zone such as America/New_York","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when tz is asked",
+			query:              "symbol=IBM&tz=Europe/London",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			expectedArgs: stockgetter.GetStockArgs{
+				Symbol:   "IBM",
+				Asset:    stockgetter.AssetStock,
+				Mode:     stockgetter.TimeModeWeekly,
+				Interval: stockgetter.TimeInterval5Min,
+			},
+		},
+		{
+			caseName:           "when asset is unknown",
+			query:              "symbol=BTC&asset=bond",
+			expectedStatusCode: http.StatusBadRequest,
+			expectedBody:       `{"code":"invalid_parameter","message":"asset must be one of stock, crypto","retryable":false}` + "\n",
+		},
+		{
+			caseName:           "when crypto is asked in a market",
+			query:              "symbol=BTC&asset=Crypto&market=eur&mode=daily",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			expectedArgs: stockgetter.GetStockArgs{
+				Symbol:   "BTC",
+				Asset:    stockgetter.AssetCrypto,
+				Market:   "EUR",
+				Mode:     stockgetter.TimeModeDaily,
+				Interval: stockgetter.TimeInterval5Min,
+			},
+		},
+		{
+			caseName:           "when mode and interval are numeric",
+			query:              "symbol=TSCO.LON&mode=1&interval=54",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       "abcd123",
+			expectedArgs: stockgetter.GetStockArgs{
+				Symbol:   "TSCO.LON",
+				Asset:    stockgetter.AssetStock,
+				Mode:     stockgetter.TimeModeDaily,
+				Interval: stockgetter.TimeInterval1Min,
+			},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		sg := &recordingStockGetter{}
+		s := NewServer(sg, successEncSvc(1))
+
+		req, err := http.NewRequest(http.MethodGet, "/?"+tt.query, nil)
+		if err != nil {
+			t.Error(logTestcase, err)
+		}
+
+		rw := httptest.NewRecorder()
+
+		s.HandleGetStock().ServeHTTP(rw, req)
+
+		if rw.Code != tt.expectedStatusCode {
+			t.Errorf("%s status code [%d] not equal expected [%d]", logTestcase, rw.Code, tt.expectedStatusCode)
+		}
+
+		if rw.Body.String() != tt.expectedBody {
+			t.Errorf("%s body [%s] not equal expected [%s]", logTestcase, rw.Body.String(), tt.expectedBody)
+		}
+
+		if !reflect.DeepEqual(sg.args, tt.expectedArgs) {
+			t.Errorf("%s args %+v not equal expected %+v", logTestcase, sg.args, tt.expectedArgs)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Package apierror is the error body every stockplay service responds with.
+package apierror
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// Code is a stable machine readable error identifier, clients should switch on
+// it rather than on the message.
+type Code string
+
+const (
+	CodeBadRequest       Code = "bad_request"
+	CodeMissingParameter Code = "missing_parameter"
+	CodeInvalidParameter Code = "invalid_parameter"
+	CodeForbidden        Code = "forbidden"
+	CodeNotAcceptable    Code = "not_acceptable"
+	CodeNotFound         Code = "not_found"
+	CodeInternal         Code = "internal_error"
+
+	CodeSymbolNotFound  Code = "symbol_not_found"
+	CodeRateLimited     Code = "rate_limited"
+	CodePremiumRequired Code = "premium_required"
+	CodeUpstreamError   Code = "upstream_error"
+	CodeUpstreamTimeout Code = "upstream_timeout"
+
+	CodeEncryptorUnavailable Code = "encryptor_unavailable"
+	CodeEncryptorError       Code = "encryptor_error"
+	CodeEncryptionFailed     Code = "encryption_failed"
+	CodeInvalidCiphertext    Code = "invalid_ciphertext"
+	CodeAuthenticationFailed Code = "authentication_failed"
+	CodeUnknownKey           Code = "unknown_key"
+)
+
+// RequestIDHeader carries the request id in both directions.
+const RequestIDHeader = "X-Request-Id"
+
+// Error is the json body of every failed response.
+type Error struct {
+	Status    int    `json:"-"`
+	Code      Code   `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id,omitempty"`
+	Retryable bool   `json:"retryable"`
+}
+
+func New(status int, code Code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message}
+}
+
+// NewRetryable is New for failures that may succeed when tried again later.
+func NewRetryable(status int, code Code, message string) *Error {
+	return &Error{Status: status, Code: code, Message: message, Retryable: true}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Write responds with e, tagged with the request id of r.
+func Write(w http.ResponseWriter, r *http.Request, e *Error) {
+	body := *e
+	body.RequestID = RequestID(r)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// Parse reads an error body written by Write, ok is false when data is not
+// one.
+func Parse(status int, data []byte) (e *Error, ok bool) {
+	var parsed Error
+	if err := json.Unmarshal(data, &parsed); err != nil || parsed.Code == "" {
+		return nil, false
+	}
+
+	parsed.Status = status
+	return &parsed, true
+}
+
+type requestIDKey struct{}
+
+// WithRequestID makes sure every request has an id, reusing the one sent by
+// the caller when there is one, and echoes it back in the response.
+func WithRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if id == "" || len(id) > 128 {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// RequestID returns the id given to r by WithRequestID, or the one sent by
+// the caller when the middleware is not in use.
+func RequestID(r *http.Request) string {
+	if id, ok := r.Context().Value(requestIDKey{}).(string); ok {
+		return id
+	}
+
+	return r.Header.Get(RequestIDHeader)
+}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,85 @@
+package apierror
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		requestID    string
+		err          *Error
+		expectedBody string
+	}{
+		{
+			caseName:     "without request id",
+			err:          New(http.StatusBadRequest, CodeMissingParameter, "symbol is required"),
+			expectedBody: `{"code":"missing_parameter","message":"symbol is required","retryable":false}` + "\n",
+		},
+		{
+			caseName:     "with request id",
+			requestID:    "abcd1234",
+			err:          NewRetryable(http.StatusTooManyRequests, CodeRateLimited, "try again later"),
+			expectedBody: `{"code":"rate_limited","message":"try again later","request_id":"abcd1234","retryable":true}` + "\n",
+		},
+	}
+
+	for _, tt := range tts {
+		t.Log(tt.caseName)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.requestID != "" {
+			req.Header.Set(RequestIDHeader, tt.requestID)
+		}
+
+		rw := httptest.NewRecorder()
+		Write(rw, req, tt.err)
+
+		if rw.Code != tt.err.Status {
+			t.Errorf("status code [%d] not equal expected [%d]", rw.Code, tt.err.Status)
+		}
+
+		if rw.Body.String() != tt.expectedBody {
+			t.Errorf("body [%s] not equal expected [%s]", rw.Body.String(), tt.expectedBody)
+		}
+
+		parsed, ok := Parse(rw.Code, rw.Body.Bytes())
+		if !ok || parsed.Code != tt.err.Code || parsed.Status != tt.err.Status || parsed.Retryable != tt.err.Retryable {
+			t.Errorf("parsed error %+v does not match written %+v", parsed, tt.err)
+		}
+	}
+}
+
+func TestParseNotAnError(t *testing.T) {
+	for _, body := range []string{"internal error", `{"points":[]}`} {
+		if _, ok := Parse(http.StatusInternalServerError, []byte(body)); ok {
+			t.Errorf("expected [%s] not to parse as an error", body)
+		}
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	var seen string
+	handler := WithRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	if seen == "" || rw.Header().Get(RequestIDHeader) != seen {
+		t.Errorf("expected generated id to be echoed, got [%s] and [%s]", seen, rw.Header().Get(RequestIDHeader))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "from-caller")
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, req)
+
+	if seen != "from-caller" || rw.Header().Get(RequestIDHeader) != "from-caller" {
+		t.Errorf("expected caller id to be kept, got [%s] and [%s]", seen, rw.Header().Get(RequestIDHeader))
+	}
+}
@@ -1,15 +1,19 @@
 package alphavantage
 
 import (
+	"bufio"
+	"bytes"
 	"context"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
-	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,32 +23,103 @@ const (
 	ModeTimeSeriesWeekly   = "TIME_SERIES_WEEKLY"
 	ModeTimeSeriesMonthly  = "TIME_SERIES_MONTHLY"
 
+	ModeTimeSeriesDailyAdjusted   = "TIME_SERIES_DAILY_ADJUSTED"
+	ModeTimeSeriesWeeklyAdjusted  = "TIME_SERIES_WEEKLY_ADJUSTED"
+	ModeTimeSeriesMonthlyAdjusted = "TIME_SERIES_MONTHLY_ADJUSTED"
+
 	Interval1min  = "1min"
 	Interval5min  = "5min"
 	Interval15min = "15min"
 	Interval30min = "30min"
 	Interval60min = "60min"
 
+	// OutputSizeCompact is the latest 100 points, OutputSizeFull the whole
+	// history. Weekly and monthly series are always sent whole.
+	OutputSizeCompact = "compact"
+	OutputSizeFull    = "full"
+
+	// DataTypeCSV is the default, DataTypeJSON also carries the meta data of
+	// a series
+	DataTypeCSV  = "csv"
+	DataTypeJSON = "json"
+
 	layoutIntraday = "2006-01-02 15:04:05"
 	layoutStd      = "2006-01-02"
 )
 
 var (
-	ErrServerResponse = errors.New("server response error")
+	ErrServerResponse  = errors.New("server response error")
+	ErrRateLimited     = errors.New("rate limited by alphavantage")
+	ErrInvalidSymbol   = errors.New("invalid symbol or api call")
+	ErrPremiumEndpoint = errors.New("premium endpoint")
+	ErrUnknownDataType = errors.New("unknown data type")
 )
 
 type Client struct {
 	httpClient *http.Client
 	host       string
 	apiKey     string
+	limiter    *rateLimiter
+	dataType   string
+}
+
+// Option configures optional Client behaviour.
+type Option func(*Client)
+
+// WithRateLimit keeps the client within limit, calls over budget wait for
+// room or fail with ErrRateLimitExceeded when limit.FailFast is set.
+func WithRateLimit(limit RateLimit) Option {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(limit, time.Now)
+	}
+}
+
+// WithDataType picks the format time series are fetched in, DataTypeCSV or
+// DataTypeJSON in any case. The other calls keep the one format they are
+// parsed from, and time series stay csv when ParseDataType rejects dataType.
+func WithDataType(dataType string) Option {
+	return func(c *Client) {
+		if dt, err := ParseDataType(dataType); err == nil {
+			c.dataType = dt
+		}
+	}
 }
 
-func NewClient(httpClient *http.Client, host, apiKey string) *Client {
-	return &Client{
+// ParseDataType returns the DataType constant dataType names, DataTypeCSV when
+// it is empty, so a misspelt setting can be refused before the client is made.
+func ParseDataType(dataType string) (string, error) {
+	switch dt := strings.ToLower(strings.TrimSpace(dataType)); dt {
+	case "":
+		return DataTypeCSV, nil
+	case DataTypeCSV, DataTypeJSON:
+		return dt, nil
+	}
+
+	return "", fmt.Errorf("%q: %w", dataType, ErrUnknownDataType)
+}
+
+func NewClient(httpClient *http.Client, host, apiKey string, opts ...Option) *Client {
+	c := &Client{
 		httpClient: httpClient,
 		host:       host,
 		apiKey:     apiKey,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// RemainingBudget reports the calls left in each rate limit window, ok is
+// false when the client has no rate limit.
+func (c *Client) RemainingBudget() (budget Budget, ok bool) {
+	if c.limiter == nil {
+		return Budget{MinuteRemaining: -1, DayRemaining: -1}, false
+	}
+
+	return c.limiter.Budget(), true
 }
 
 type Stock struct {
@@ -54,24 +129,89 @@ type Stock struct {
 	Close  float64
 	Volume int64
 	Date   time.Time
+
+	// the adjusted series also carry the corporate actions of each point,
+	// they are left zero by the others
+	AdjustedClose    float64
+	DividendAmount   float64
+	SplitCoefficient float64
 }
 
 type GetStockArgs struct {
 	Mode     string
 	Interval string
 	Symbol   string
+
+	// OutputSize is left to alphavantage, which sends a compact series, when
+	// empty
+	OutputSize string
 }
 
 func (c *Client) GetStockTimeSeries(ctx context.Context, args GetStockArgs) ([]Stock, error) {
+	series, err := c.GetStockSeries(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return series.Stocks, nil
+}
+
+// GetStockSeries fetches a time series along with its meta data, which is
+// left empty unless the client fetches json. Intraday timestamps are read in
+// the zone the meta data names, or in the zone of the symbol's exchange when
+// there is none.
+func (c *Client) GetStockSeries(ctx context.Context, args GetStockArgs) (Series, error) {
+	dataType := c.dataType
+	if dataType == "" {
+		dataType = DataTypeCSV
+	}
+
+	body, err := c.query(ctx, seriesQuery(args, dataType))
+	if err != nil {
+		return Series{}, err
+	}
+	defer body.Close()
+
+	if dataType == DataTypeJSON {
+		return parseJSONBody(args.Mode, SymbolTimeZone(args.Symbol), body)
+	}
+
+	loc, err := seriesLocation(args.Mode, SymbolTimeZone(args.Symbol))
+	if err != nil {
+		return Series{}, err
+	}
+
+	stocks, err := parseBody(args.Mode, loc, body)
+	return Series{Stocks: stocks}, err
+}
+
+func seriesQuery(args GetStockArgs, dataType string) url.Values {
 	q := url.Values{}
 	q.Set("function", args.Mode)
 	q.Set("symbol", args.Symbol)
-	q.Set("apikey", c.apiKey)
-	q.Set("datatype", "csv")
+	q.Set("datatype", dataType)
 	if args.Mode == ModeTimeSeriesIntraday {
 		q.Set("interval", args.Interval)
 	}
 
+	if args.OutputSize != "" {
+		q.Set("outputsize", args.OutputSize)
+	}
+
+	return q
+}
+
+// query calls the api with q and the client's api key, the caller must close
+// the returned body.
+func (c *Client) query(ctx context.Context, q url.Values) (io.ReadCloser, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("failed to wait for rate limit: %w", err)
+		}
+	}
+
+	q.Set("apikey", c.apiKey)
+
 	urlpath := c.host + "/query?" + q.Encode()
 
 	req, err := http.NewRequest(http.MethodGet, urlpath, nil)
@@ -85,76 +225,103 @@ func (c *Client) GetStockTimeSeries(ctx context.Context, args GetStockArgs) ([]S
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+
 		dumpedResponse, _ := httputil.DumpResponse(resp, true)
 
 		return nil, fmt.Errorf("got error response \n%s\n: %w", string(dumpedResponse), ErrServerResponse)
 	}
 
-	return parseBody(args.Mode, resp.Body)
+	return checkErrorBody(resp.Body)
 }
 
-func parseBody(mode string, body io.Reader) ([]Stock, error) {
-	csvReader := csv.NewReader(body)
-	csvReader.LazyQuotes = true
+// errorBody is what alphavantage sends with status 200 when a call is
+// throttled, needs a premium key or has a bad symbol.
+type errorBody struct {
+	Note         string `json:"Note"`
+	Information  string `json:"Information"`
+	ErrorMessage string `json:"Error Message"`
+}
 
-	csvReader.Read() // skip header
+// checkErrorBody turns an error payload into one of the sentinel errors. Any
+// other body is returned unread, json ones are buffered since they have to be
+// decoded to be told apart.
+func checkErrorBody(body io.ReadCloser) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(body)
 
-	layout := layoutStd
-	if mode == ModeTimeSeriesIntraday {
-		layout = layoutIntraday
+	// an empty body fails the peek and is left for the parser to report
+	first, err := buffered.Peek(1)
+	if err != nil || first[0] != '{' {
+		return readCloser{buffered, body}, nil
 	}
+	defer body.Close()
 
-	var stocks []Stock
-	for {
-		row, err := csvReader.Read()
-		if err != nil {
-			if err == io.EOF {
-				return stocks, nil
-			}
+	data, err := ioutil.ReadAll(buffered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
 
-			return stocks, fmt.Errorf("error reading csv row: %w", err)
-		}
+	var errBody errorBody
+	if err := json.Unmarshal(data, &errBody); err != nil {
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
 
-		date, err := time.Parse(layout, row[0])
-		if err != nil {
-			return stocks, fmt.Errorf("failed to parse timestamp %s: %w", row[0], err)
+	switch {
+	case errBody.ErrorMessage != "":
+		return nil, fmt.Errorf("%s: %w", errBody.ErrorMessage, ErrInvalidSymbol)
+	case errBody.Note != "":
+		return nil, fmt.Errorf("%s: %w", errBody.Note, ErrRateLimited)
+	case errBody.Information != "":
+		if strings.Contains(strings.ToLower(errBody.Information), "premium") {
+			return nil, fmt.Errorf("%s: %w", errBody.Information, ErrPremiumEndpoint)
 		}
 
-		open, err := strconv.ParseFloat(row[1], 64)
-		if err != nil {
-			return stocks, fmt.Errorf("failed to parse open field %s: %w", row[1], err)
-		}
+		return nil, fmt.Errorf("%s: %w", errBody.Information, ErrRateLimited)
+	}
 
-		high, err := strconv.ParseFloat(row[2], 64)
-		if err != nil {
-			return stocks, fmt.Errorf("failed to parse high field %s: %w", row[2], err)
-		}
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
 
-		low, err := strconv.ParseFloat(row[3], 64)
-		if err != nil {
-			return stocks, fmt.Errorf("failed to parse low field %s: %w", row[3], err)
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// readRecords reads a csv body into one map per row keyed by the header names.
+func readRecords(body io.Reader) ([]map[string]string, error) {
+	csvReader := csv.NewReader(body)
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
 		}
 
-		cls, err := strconv.ParseFloat(row[4], 64)
+		return nil, fmt.Errorf("error reading csv header: %w", err)
+	}
+
+	var records []map[string]string
+	for {
+		row, err := csvReader.Read()
 		if err != nil {
-			return stocks, fmt.Errorf("failed to parse close field %s: %w", row[4], err)
+			if err == io.EOF {
+				return records, nil
+			}
+
+			return records, fmt.Errorf("error reading csv row: %w", err)
 		}
 
-		volume, err := strconv.ParseInt(row[5], 10, 64)
-		if err != nil {
-			return stocks, fmt.Errorf("failed to parse open field %s: %w", row[5], err)
+		record := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(row) {
+				record[name] = row[i]
+			}
 		}
 
-		stocks = append(stocks, Stock{
-			Open:   open,
-			High:   high,
-			Low:    low,
-			Close:  cls,
-			Volume: volume,
-			Date:   date,
-		})
+		records = append(records, record)
 	}
 }
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -16,6 +18,7 @@ func TestClient_GetStockTimeSeries(t *testing.T) {
 		mode         string
 		symbol       string
 		interval     string
+		outputSize   string
 		handler      func(logtag string, t *testing.T) http.HandlerFunc
 		expectedResp []Stock
 		expectedErr  error
@@ -52,6 +55,62 @@ func TestClient_GetStockTimeSeries(t *testing.T) {
 			expectedResp: nil,
 			expectedErr:  ErrServerResponse,
 		},
+		{
+			caseName: "when throttled",
+			mode:     ModeTimeSeriesIntraday,
+			symbol:   "abcde",
+			interval: Interval5min,
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte("{\n    \"Note\": \"Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute and 500 calls per day.\"\n}"))
+				}
+			},
+			expectedResp: nil,
+			expectedErr:  ErrRateLimited,
+		},
+		{
+			caseName: "when daily limit reached",
+			mode:     ModeTimeSeriesIntraday,
+			symbol:   "abcde",
+			interval: Interval5min,
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte("{\n    \"Information\": \"Our standard API rate limit is 25 requests per day.\"\n}"))
+				}
+			},
+			expectedResp: nil,
+			expectedErr:  ErrRateLimited,
+		},
+		{
+			caseName: "when premium endpoint",
+			mode:     ModeTimeSeriesIntraday,
+			symbol:   "abcde",
+			interval: Interval5min,
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte("{\n    \"Information\": \"Thank you for using Alpha Vantage! This is a premium endpoint.\"\n}"))
+				}
+			},
+			expectedResp: nil,
+			expectedErr:  ErrPremiumEndpoint,
+		},
+		{
+			caseName: "when invalid symbol",
+			mode:     ModeTimeSeriesIntraday,
+			symbol:   "abcde",
+			interval: Interval5min,
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte("{\n    \"Error Message\": \"Invalid API call. Please retry or visit the documentation (https://www.alphavantage.co/documentation/) for TIME_SERIES_INTRADAY.\"\n}"))
+				}
+			},
+			expectedResp: nil,
+			expectedErr:  ErrInvalidSymbol,
+		},
 		{
 			caseName: "when success intraday",
 			mode:     ModeTimeSeriesIntraday,
@@ -89,7 +148,8 @@ func TestClient_GetStockTimeSeries(t *testing.T) {
 					Low:    114.4400,
 					Close:  114.4400,
 					Volume: 457,
-					Date:   time.Date(2020, 11, 6, 20, 0, 0, 0, time.UTC),
+					// 20:00 US/Eastern, after daylight saving ended
+					Date: time.Date(2020, 11, 7, 1, 0, 0, 0, time.UTC),
 				},
 			},
 			expectedErr: nil,
@@ -131,6 +191,36 @@ func TestClient_GetStockTimeSeries(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			caseName:   "when full output is asked",
+			mode:       ModeTimeSeriesDaily,
+			symbol:     "abcde",
+			outputSize: OutputSizeFull,
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					q := r.URL.Query()
+
+					if q.Get("outputsize") != OutputSizeFull {
+						t.Errorf("%s outputsize [%s] is not equal [%s]", logtag, q.Get("outputsize"), OutputSizeFull)
+					}
+
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`timestamp,open,high,low,close,volume
+2020-11-06,114.4400,114.4400,114.4400,114.4400,457`))
+				}
+			},
+			expectedResp: []Stock{
+				{
+					Open:   114.4400,
+					High:   114.4400,
+					Low:    114.4400,
+					Close:  114.4400,
+					Volume: 457,
+					Date:   time.Date(2020, 11, 6, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			expectedErr: nil,
+		},
 	}
 
 	for idx, tt := range tts {
@@ -146,14 +236,13 @@ func TestClient_GetStockTimeSeries(t *testing.T) {
 		}
 
 		resp, err := c.GetStockTimeSeries(context.Background(), GetStockArgs{
-			Mode:     tt.mode,
-			Interval: tt.interval,
-			Symbol:   tt.symbol,
+			Mode:       tt.mode,
+			Interval:   tt.interval,
+			Symbol:     tt.symbol,
+			OutputSize: tt.outputSize,
 		})
-		if err != nil {
-			if !errors.Is(err, tt.expectedErr) {
-				t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
-			}
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
 		}
 
 		if len(resp) != len(tt.expectedResp) {
@@ -189,3 +278,49 @@ func TestClient_GetStockTimeSeries(t *testing.T) {
 		srv.Close()
 	}
 }
+
+func TestParseBodyAdjusted(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		mode         string
+		body         string
+		expectedResp []Stock
+	}{
+		{
+			caseName: "daily adjusted",
+			mode:     ModeTimeSeriesDailyAdjusted,
+			body: `timestamp,open,high,low,close,adjusted_close,volume,dividend_amount,split_coefficient
+2020-08-31,127.58,130.0,126.0,129.04,129.04,225702700,0.0000,4.0`,
+			expectedResp: []Stock{{
+				Open: 127.58, High: 130, Low: 126, Close: 129.04, Volume: 225702700,
+				Date:          time.Date(2020, 8, 31, 0, 0, 0, 0, time.UTC),
+				AdjustedClose: 129.04, SplitCoefficient: 4,
+			}},
+		},
+		{
+			caseName: "weekly adjusted spells columns with spaces",
+			mode:     ModeTimeSeriesWeeklyAdjusted,
+			body: `timestamp,open,high,low,close,adjusted close,volume,dividend amount
+2020-11-06,114.44,116.0,113.0,114.04,112.4,4573000,1.63`,
+			expectedResp: []Stock{{
+				Open: 114.44, High: 116, Low: 113, Close: 114.04, Volume: 4573000,
+				Date:          time.Date(2020, 11, 6, 0, 0, 0, 0, time.UTC),
+				AdjustedClose: 112.4, DividendAmount: 1.63,
+			}},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		resp, err := parseBody(tt.mode, time.UTC, strings.NewReader(tt.body))
+		if err != nil {
+			t.Error(logTestcase, "unexpected err", err)
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received %+v not equal expected %+v", logTestcase, resp, tt.expectedResp)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package alphavantage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ModeCryptoIntraday         = "CRYPTO_INTRADAY"
+	ModeDigitalCurrencyDaily   = "DIGITAL_CURRENCY_DAILY"
+	ModeDigitalCurrencyWeekly  = "DIGITAL_CURRENCY_WEEKLY"
+	ModeDigitalCurrencyMonthly = "DIGITAL_CURRENCY_MONTHLY"
+)
+
+type GetCryptoArgs struct {
+	Mode string
+	// Interval is only sent with ModeCryptoIntraday
+	Interval string
+	Symbol   string
+	// Market is the currency prices are quoted in, such as USD or EUR
+	Market string
+
+	// OutputSize is only sent with ModeCryptoIntraday, the digital currency
+	// series always come whole
+	OutputSize string
+}
+
+// GetCryptoTimeSeries fetches a digital currency series in the Stock shape of
+// the equity series, volumes are rounded to whole units.
+func (c *Client) GetCryptoTimeSeries(ctx context.Context, args GetCryptoArgs) ([]Stock, error) {
+	q := url.Values{}
+	q.Set("function", args.Mode)
+	q.Set("symbol", args.Symbol)
+	q.Set("market", args.Market)
+	q.Set("datatype", "csv")
+	if args.Mode == ModeCryptoIntraday {
+		q.Set("interval", args.Interval)
+
+		if args.OutputSize != "" {
+			q.Set("outputsize", args.OutputSize)
+		}
+	}
+
+	body, err := c.query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return parseCryptoBody(args.Mode, args.Market, body)
+}
+
+// cryptoField returns the column name of a row. The digital currency series
+// have sent prices per market, as "open (USD)", next to the USD ones, the
+// market column is preferred when there is one.
+func cryptoField(record map[string]string, name, market string) (string, bool) {
+	if v, ok := record[fmt.Sprintf("%s (%s)", name, strings.ToUpper(market))]; ok {
+		return v, true
+	}
+
+	v, ok := record[name]
+	return v, ok
+}
+
+func parseCryptoBody(mode, market string, body io.Reader) ([]Stock, error) {
+	records, err := readSeriesRecords(body)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := layoutStd
+	if mode == ModeCryptoIntraday {
+		layout = layoutIntraday
+	}
+
+	stocks := make([]Stock, 0, len(records))
+	for _, record := range records {
+		var stock Stock
+
+		stock.Date, err = time.Parse(layout, record["timestamp"])
+		if err != nil {
+			return stocks, fmt.Errorf("failed to parse timestamp %s: %w", record["timestamp"], err)
+		}
+
+		floats := []struct {
+			name string
+			dst  *float64
+		}{
+			{"open", &stock.Open},
+			{"high", &stock.High},
+			{"low", &stock.Low},
+			{"close", &stock.Close},
+		}
+		for _, f := range floats {
+			v, _ := cryptoField(record, f.name, market)
+			*f.dst, err = strconv.ParseFloat(v, 64)
+			if err != nil {
+				return stocks, fmt.Errorf("failed to parse %s field %s: %w", f.name, v, err)
+			}
+		}
+
+		// crypto volumes are fractional
+		volume, err := strconv.ParseFloat(record["volume"], 64)
+		if err != nil {
+			return stocks, fmt.Errorf("failed to parse volume field %s: %w", record["volume"], err)
+		}
+		stock.Volume = int64(math.Round(volume))
+
+		stocks = append(stocks, stock)
+	}
+
+	return stocks, nil
+}
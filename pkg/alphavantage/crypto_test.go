@@ -0,0 +1,93 @@
+package alphavantage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_GetCryptoTimeSeries(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		args         GetCryptoArgs
+		body         string
+		expectedResp []Stock
+	}{
+		{
+			caseName: "when daily with market columns",
+			args:     GetCryptoArgs{Mode: ModeDigitalCurrencyDaily, Symbol: "BTC", Market: "EUR"},
+			body: `timestamp,open (EUR),high (EUR),low (EUR),close (EUR),open (USD),high (USD),low (USD),close (USD),volume,market cap (USD)
+2021-05-20,33000.10,34000.20,32000.30,33500.40,40000.10,41000.20,39000.30,40500.40,1234.56,1234.56`,
+			expectedResp: []Stock{
+				{Open: 33000.10, High: 34000.20, Low: 32000.30, Close: 33500.40, Volume: 1235, Date: time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			caseName: "when daily with plain columns",
+			args:     GetCryptoArgs{Mode: ModeDigitalCurrencyDaily, Symbol: "BTC", Market: "EUR"},
+			body: `timestamp,open,high,low,close,volume
+2021-05-20,33000.10,34000.20,32000.30,33500.40,1234.4`,
+			expectedResp: []Stock{
+				{Open: 33000.10, High: 34000.20, Low: 32000.30, Close: 33500.40, Volume: 1234, Date: time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			caseName: "when intraday",
+			args:     GetCryptoArgs{Mode: ModeCryptoIntraday, Interval: Interval5min, Symbol: "ETH", Market: "USD", OutputSize: OutputSizeFull},
+			body: `timestamp,open,high,low,close,volume
+2021-05-20 14:05:00,2500.5,2510,2490,2505.25,310`,
+			expectedResp: []Stock{
+				{Open: 2500.5, High: 2510, Low: 2490, Close: 2505.25, Volume: 310, Date: time.Date(2021, 5, 20, 14, 5, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+
+			if q.Get("function") != tt.args.Mode {
+				t.Errorf("%s mode [%s] is not equal [%s]", logTestcase, q.Get("function"), tt.args.Mode)
+			}
+
+			if q.Get("symbol") != tt.args.Symbol || q.Get("market") != tt.args.Market {
+				t.Errorf("%s symbol [%s/%s] is not equal [%s/%s]", logTestcase, q.Get("symbol"), q.Get("market"), tt.args.Symbol, tt.args.Market)
+			}
+
+			if q.Get("interval") != tt.args.Interval {
+				t.Errorf("%s interval [%s] is not equal [%s]", logTestcase, q.Get("interval"), tt.args.Interval)
+			}
+
+			if q.Get("outputsize") != tt.args.OutputSize {
+				t.Errorf("%s outputsize [%s] is not equal [%s]", logTestcase, q.Get("outputsize"), tt.args.OutputSize)
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(tt.body))
+		}))
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       srv.URL,
+			apiKey:     "demo",
+		}
+
+		resp, err := c.GetCryptoTimeSeries(context.Background(), tt.args)
+		if err != nil {
+			t.Error(logTestcase, "unexpected err", err)
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
+		}
+
+		srv.Close()
+	}
+}
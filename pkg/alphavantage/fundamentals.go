@@ -0,0 +1,330 @@
+package alphavantage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	ModeIncomeStatement = "INCOME_STATEMENT"
+	ModeBalanceSheet    = "BALANCE_SHEET"
+	ModeCashFlow        = "CASH_FLOW"
+	ModeEarnings        = "EARNINGS"
+)
+
+var (
+	ErrEmptyFundamentals = errors.New("fundamentals response has no data")
+)
+
+type IncomeStatement struct {
+	FiscalDateEnding  time.Time
+	ReportedCurrency  string
+	TotalRevenue      float64
+	CostOfRevenue     float64
+	GrossProfit       float64
+	OperatingExpenses float64
+	OperatingIncome   float64
+	InterestExpense   float64
+	IncomeTaxExpense  float64
+	EBITDA            float64
+	NetIncome         float64
+}
+
+type BalanceSheet struct {
+	FiscalDateEnding        time.Time
+	ReportedCurrency        string
+	TotalAssets             float64
+	TotalCurrentAssets      float64
+	CashAndEquivalents      float64
+	Inventory               float64
+	TotalLiabilities        float64
+	TotalCurrentLiabilities float64
+	LongTermDebt            float64
+	TotalShareholderEquity  float64
+	SharesOutstanding       float64
+}
+
+type CashFlow struct {
+	FiscalDateEnding      time.Time
+	ReportedCurrency      string
+	OperatingCashflow     float64
+	CapitalExpenditures   float64
+	CashflowFromInvesting float64
+	CashflowFromFinancing float64
+	DividendPayout        float64
+	NetIncome             float64
+}
+
+type Earnings struct {
+	FiscalDateEnding time.Time
+	// ReportedDate is only known for quarterly earnings
+	ReportedDate time.Time
+	ReportedEPS  float64
+	// EstimatedEPS, Surprise and SurprisePercentage are only known for
+	// quarterly earnings
+	EstimatedEPS       float64
+	Surprise           float64
+	SurprisePercentage float64
+}
+
+type IncomeStatements struct {
+	Symbol    string
+	Annual    []IncomeStatement
+	Quarterly []IncomeStatement
+}
+
+type BalanceSheets struct {
+	Symbol    string
+	Annual    []BalanceSheet
+	Quarterly []BalanceSheet
+}
+
+type CashFlows struct {
+	Symbol    string
+	Annual    []CashFlow
+	Quarterly []CashFlow
+}
+
+type EarningsHistory struct {
+	Symbol    string
+	Annual    []Earnings
+	Quarterly []Earnings
+}
+
+// reportsBody is the json of every fundamentals call, each report is a flat
+// object of strings where missing figures are "None".
+type reportsBody struct {
+	Symbol            string              `json:"symbol"`
+	AnnualReports     []map[string]string `json:"annualReports"`
+	QuarterlyReports  []map[string]string `json:"quarterlyReports"`
+	AnnualEarnings    []map[string]string `json:"annualEarnings"`
+	QuarterlyEarnings []map[string]string `json:"quarterlyEarnings"`
+}
+
+func (c *Client) getReports(ctx context.Context, function, symbol string) (reportsBody, error) {
+	q := url.Values{}
+	q.Set("function", function)
+	q.Set("symbol", symbol)
+
+	body, err := c.query(ctx, q)
+	if err != nil {
+		return reportsBody{}, err
+	}
+	defer body.Close()
+
+	var reports reportsBody
+	if err := json.NewDecoder(body).Decode(&reports); err != nil {
+		return reportsBody{}, fmt.Errorf("failed to decode %s: %w", function, err)
+	}
+
+	// an unknown symbol comes back as an empty object
+	if reports.Symbol == "" {
+		return reportsBody{}, ErrEmptyFundamentals
+	}
+
+	return reports, nil
+}
+
+func (c *Client) GetIncomeStatement(ctx context.Context, symbol string) (IncomeStatements, error) {
+	reports, err := c.getReports(ctx, ModeIncomeStatement, symbol)
+	if err != nil {
+		return IncomeStatements{}, err
+	}
+
+	parse := func(records []map[string]string) ([]IncomeStatement, error) {
+		statements := make([]IncomeStatement, 0, len(records))
+		for _, record := range records {
+			f := figures{record: record}
+			statements = append(statements, IncomeStatement{
+				FiscalDateEnding:  f.date("fiscalDateEnding"),
+				ReportedCurrency:  record["reportedCurrency"],
+				TotalRevenue:      f.float("totalRevenue"),
+				CostOfRevenue:     f.float("costOfRevenue"),
+				GrossProfit:       f.float("grossProfit"),
+				OperatingExpenses: f.float("operatingExpenses"),
+				OperatingIncome:   f.float("operatingIncome"),
+				InterestExpense:   f.float("interestExpense"),
+				IncomeTaxExpense:  f.float("incomeTaxExpense"),
+				EBITDA:            f.float("ebitda"),
+				NetIncome:         f.float("netIncome"),
+			})
+			if f.err != nil {
+				return nil, f.err
+			}
+		}
+
+		return statements, nil
+	}
+
+	res := IncomeStatements{Symbol: reports.Symbol}
+	if res.Annual, err = parse(reports.AnnualReports); err != nil {
+		return IncomeStatements{}, err
+	}
+
+	if res.Quarterly, err = parse(reports.QuarterlyReports); err != nil {
+		return IncomeStatements{}, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) GetBalanceSheet(ctx context.Context, symbol string) (BalanceSheets, error) {
+	reports, err := c.getReports(ctx, ModeBalanceSheet, symbol)
+	if err != nil {
+		return BalanceSheets{}, err
+	}
+
+	parse := func(records []map[string]string) ([]BalanceSheet, error) {
+		sheets := make([]BalanceSheet, 0, len(records))
+		for _, record := range records {
+			f := figures{record: record}
+			sheets = append(sheets, BalanceSheet{
+				FiscalDateEnding:        f.date("fiscalDateEnding"),
+				ReportedCurrency:        record["reportedCurrency"],
+				TotalAssets:             f.float("totalAssets"),
+				TotalCurrentAssets:      f.float("totalCurrentAssets"),
+				CashAndEquivalents:      f.float("cashAndCashEquivalentsAtCarryingValue"),
+				Inventory:               f.float("inventory"),
+				TotalLiabilities:        f.float("totalLiabilities"),
+				TotalCurrentLiabilities: f.float("totalCurrentLiabilities"),
+				LongTermDebt:            f.float("longTermDebt"),
+				TotalShareholderEquity:  f.float("totalShareholderEquity"),
+				SharesOutstanding:       f.float("commonStockSharesOutstanding"),
+			})
+			if f.err != nil {
+				return nil, f.err
+			}
+		}
+
+		return sheets, nil
+	}
+
+	res := BalanceSheets{Symbol: reports.Symbol}
+	if res.Annual, err = parse(reports.AnnualReports); err != nil {
+		return BalanceSheets{}, err
+	}
+
+	if res.Quarterly, err = parse(reports.QuarterlyReports); err != nil {
+		return BalanceSheets{}, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) GetCashFlow(ctx context.Context, symbol string) (CashFlows, error) {
+	reports, err := c.getReports(ctx, ModeCashFlow, symbol)
+	if err != nil {
+		return CashFlows{}, err
+	}
+
+	parse := func(records []map[string]string) ([]CashFlow, error) {
+		flows := make([]CashFlow, 0, len(records))
+		for _, record := range records {
+			f := figures{record: record}
+			flows = append(flows, CashFlow{
+				FiscalDateEnding:      f.date("fiscalDateEnding"),
+				ReportedCurrency:      record["reportedCurrency"],
+				OperatingCashflow:     f.float("operatingCashflow"),
+				CapitalExpenditures:   f.float("capitalExpenditures"),
+				CashflowFromInvesting: f.float("cashflowFromInvestment"),
+				CashflowFromFinancing: f.float("cashflowFromFinancing"),
+				DividendPayout:        f.float("dividendPayout"),
+				NetIncome:             f.float("netIncome"),
+			})
+			if f.err != nil {
+				return nil, f.err
+			}
+		}
+
+		return flows, nil
+	}
+
+	res := CashFlows{Symbol: reports.Symbol}
+	if res.Annual, err = parse(reports.AnnualReports); err != nil {
+		return CashFlows{}, err
+	}
+
+	if res.Quarterly, err = parse(reports.QuarterlyReports); err != nil {
+		return CashFlows{}, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) GetEarnings(ctx context.Context, symbol string) (EarningsHistory, error) {
+	reports, err := c.getReports(ctx, ModeEarnings, symbol)
+	if err != nil {
+		return EarningsHistory{}, err
+	}
+
+	parse := func(records []map[string]string) ([]Earnings, error) {
+		earnings := make([]Earnings, 0, len(records))
+		for _, record := range records {
+			f := figures{record: record}
+			earnings = append(earnings, Earnings{
+				FiscalDateEnding:   f.date("fiscalDateEnding"),
+				ReportedDate:       f.date("reportedDate"),
+				ReportedEPS:        f.float("reportedEPS"),
+				EstimatedEPS:       f.float("estimatedEPS"),
+				Surprise:           f.float("surprise"),
+				SurprisePercentage: f.float("surprisePercentage"),
+			})
+			if f.err != nil {
+				return nil, f.err
+			}
+		}
+
+		return earnings, nil
+	}
+
+	res := EarningsHistory{Symbol: reports.Symbol}
+	if res.Annual, err = parse(reports.AnnualEarnings); err != nil {
+		return EarningsHistory{}, err
+	}
+
+	if res.Quarterly, err = parse(reports.QuarterlyEarnings); err != nil {
+		return EarningsHistory{}, err
+	}
+
+	return res, nil
+}
+
+// figures reads the fields of one report, absent and missing figures read as
+// zero and the first malformed one is kept in err.
+type figures struct {
+	record map[string]string
+	err    error
+}
+
+func (f *figures) float(name string) float64 {
+	v := f.record[name]
+	if f.err != nil || missingFigure(v) {
+		return 0
+	}
+
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		f.err = fmt.Errorf("failed to parse %s field %s: %w", name, v, err)
+	}
+
+	return n
+}
+
+func (f *figures) date(name string) time.Time {
+	v := f.record[name]
+	if f.err != nil || missingFigure(v) {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(layoutStd, v)
+	if err != nil {
+		f.err = fmt.Errorf("failed to parse %s field %s: %w", name, v, err)
+	}
+
+	return t
+}
@@ -0,0 +1,180 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestClient_GetIncomeStatement(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		body         string
+		expectedResp IncomeStatements
+		expectedErr  error
+	}{
+		{
+			caseName:    "when symbol is unknown",
+			body:        `{}`,
+			expectedErr: ErrEmptyFundamentals,
+		},
+		{
+			caseName:    "when rate limited",
+			body:        `{"Note": "Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute."}`,
+			expectedErr: ErrRateLimited,
+		},
+		{
+			caseName: "when success",
+			body: `{
+    "symbol": "abcde",
+    "annualReports": [
+        {
+            "fiscalDateEnding": "2020-12-31",
+            "reportedCurrency": "USD",
+            "grossProfit": "35575000000",
+            "totalRevenue": "73620000000",
+            "costOfRevenue": "38045000000",
+            "operatingIncome": "4609000000",
+            "interestExpense": "None",
+            "ebitda": "11528000000",
+            "netIncome": "5590000000"
+        }
+    ],
+    "quarterlyReports": [
+        {
+            "fiscalDateEnding": "2021-03-31",
+            "reportedCurrency": "USD",
+            "totalRevenue": "17730000000",
+            "netIncome": "955000000"
+        }
+    ]
+}`,
+			expectedResp: IncomeStatements{
+				Symbol: "abcde",
+				Annual: []IncomeStatement{
+					{
+						FiscalDateEnding: time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC),
+						ReportedCurrency: "USD",
+						TotalRevenue:     73620000000,
+						CostOfRevenue:    38045000000,
+						GrossProfit:      35575000000,
+						OperatingIncome:  4609000000,
+						EBITDA:           11528000000,
+						NetIncome:        5590000000,
+					},
+				},
+				Quarterly: []IncomeStatement{
+					{
+						FiscalDateEnding: time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC),
+						ReportedCurrency: "USD",
+						TotalRevenue:     17730000000,
+						NetIncome:        955000000,
+					},
+				},
+			},
+		},
+		{
+			caseName:    "when a figure is malformed",
+			body:        `{"symbol": "abcde", "annualReports": [{"fiscalDateEnding": "2020-12-31", "totalRevenue": "lots"}]}`,
+			expectedErr: strconv.ErrSyntax,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		srv := httptest.NewServer(reportsHandler(logTestcase, t, ModeIncomeStatement, tt.body))
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       srv.URL,
+			apiKey:     "demo",
+		}
+
+		resp, err := c.GetIncomeStatement(context.Background(), "abcde")
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
+		}
+
+		srv.Close()
+	}
+}
+
+func TestClient_GetEarnings(t *testing.T) {
+	srv := httptest.NewServer(reportsHandler("", t, ModeEarnings, `{
+    "symbol": "abcde",
+    "annualEarnings": [
+        {"fiscalDateEnding": "2020-12-31", "reportedEPS": "8.67"}
+    ],
+    "quarterlyEarnings": [
+        {
+            "fiscalDateEnding": "2021-03-31",
+            "reportedDate": "2021-04-19",
+            "reportedEPS": "1.77",
+            "estimatedEPS": "1.63",
+            "surprise": "0.14",
+            "surprisePercentage": "8.589"
+        }
+    ]
+}`))
+	defer srv.Close()
+
+	c := Client{
+		httpClient: http.DefaultClient,
+		host:       srv.URL,
+		apiKey:     "demo",
+	}
+
+	resp, err := c.GetEarnings(context.Background(), "abcde")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedResp := EarningsHistory{
+		Symbol: "abcde",
+		Annual: []Earnings{
+			{FiscalDateEnding: time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC), ReportedEPS: 8.67},
+		},
+		Quarterly: []Earnings{
+			{
+				FiscalDateEnding:   time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC),
+				ReportedDate:       time.Date(2021, 4, 19, 0, 0, 0, 0, time.UTC),
+				ReportedEPS:        1.77,
+				EstimatedEPS:       1.63,
+				Surprise:           0.14,
+				SurprisePercentage: 8.589,
+			},
+		},
+	}
+	if !reflect.DeepEqual(resp, expectedResp) {
+		t.Errorf("received value %+v not equal expected value %+v", resp, expectedResp)
+	}
+}
+
+func reportsHandler(logtag string, t *testing.T, function, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		if q.Get("function") != function {
+			t.Errorf("%s mode [%s] is not equal [%s]", logtag, q.Get("function"), function)
+		}
+
+		if q.Get("symbol") != "abcde" {
+			t.Errorf("%s symbol [%s] is not equal [%s]", logtag, q.Get("symbol"), "abcde")
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}
+}
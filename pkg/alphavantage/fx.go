@@ -0,0 +1,196 @@
+package alphavantage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	ModeCurrencyExchangeRate = "CURRENCY_EXCHANGE_RATE"
+
+	ModeFXIntraday = "FX_INTRADAY"
+	ModeFXDaily    = "FX_DAILY"
+	ModeFXWeekly   = "FX_WEEKLY"
+	ModeFXMonthly  = "FX_MONTHLY"
+)
+
+var (
+	ErrEmptyExchangeRate = errors.New("exchange rate response has no data")
+)
+
+// ExchangeRate is the realtime rate of one unit of From in To.
+type ExchangeRate struct {
+	From          string
+	To            string
+	Rate          float64
+	Bid           float64
+	Ask           float64
+	LastRefreshed time.Time
+}
+
+// exchangeRateBody is the json alphavantage sends, values are strings keyed
+// by numbered names.
+type exchangeRateBody struct {
+	Rate struct {
+		From          string `json:"1. From_Currency Code"`
+		To            string `json:"3. To_Currency Code"`
+		Rate          string `json:"5. Exchange Rate"`
+		LastRefreshed string `json:"6. Last Refreshed"`
+		Bid           string `json:"8. Bid Price"`
+		Ask           string `json:"9. Ask Price"`
+	} `json:"Realtime Currency Exchange Rate"`
+}
+
+// GetExchangeRate fetches the latest rate from one currency to another, which
+// only comes as json. Physical and digital currencies are both accepted.
+func (c *Client) GetExchangeRate(ctx context.Context, from, to string) (ExchangeRate, error) {
+	q := url.Values{}
+	q.Set("function", ModeCurrencyExchangeRate)
+	q.Set("from_currency", from)
+	q.Set("to_currency", to)
+
+	body, err := c.query(ctx, q)
+	if err != nil {
+		return ExchangeRate{}, err
+	}
+	defer body.Close()
+
+	return parseExchangeRate(body)
+}
+
+func parseExchangeRate(body io.Reader) (ExchangeRate, error) {
+	var raw exchangeRateBody
+	if err := json.NewDecoder(body).Decode(&raw); err != nil {
+		return ExchangeRate{}, fmt.Errorf("failed to decode exchange rate: %w", err)
+	}
+
+	if raw.Rate.From == "" {
+		return ExchangeRate{}, ErrEmptyExchangeRate
+	}
+
+	rate := ExchangeRate{
+		From: raw.Rate.From,
+		To:   raw.Rate.To,
+	}
+
+	var err error
+	rate.LastRefreshed, err = time.Parse(layoutIntraday, raw.Rate.LastRefreshed)
+	if err != nil {
+		return ExchangeRate{}, fmt.Errorf("failed to parse last refreshed %s: %w", raw.Rate.LastRefreshed, err)
+	}
+
+	floats := []struct {
+		name  string
+		value string
+		dst   *float64
+	}{
+		{"Exchange Rate", raw.Rate.Rate, &rate.Rate},
+		{"Bid Price", raw.Rate.Bid, &rate.Bid},
+		{"Ask Price", raw.Rate.Ask, &rate.Ask},
+	}
+	for _, f := range floats {
+		if missingFigure(f.value) {
+			continue
+		}
+
+		*f.dst, err = strconv.ParseFloat(f.value, 64)
+		if err != nil {
+			return ExchangeRate{}, fmt.Errorf("failed to parse %s field %s: %w", f.name, f.value, err)
+		}
+	}
+
+	return rate, nil
+}
+
+// FXRate is one point of an exchange rate series, the price of one unit of
+// From in To. FX series have no volume.
+type FXRate struct {
+	Open  float64
+	High  float64
+	Low   float64
+	Close float64
+	Date  time.Time
+}
+
+type GetFXArgs struct {
+	Mode string
+	// Interval is only sent with ModeFXIntraday
+	Interval string
+	From     string
+	To       string
+
+	// OutputSize is left to alphavantage, which sends a compact series, when
+	// empty
+	OutputSize string
+}
+
+func (c *Client) GetFXTimeSeries(ctx context.Context, args GetFXArgs) ([]FXRate, error) {
+	q := url.Values{}
+	q.Set("function", args.Mode)
+	q.Set("from_symbol", args.From)
+	q.Set("to_symbol", args.To)
+	q.Set("datatype", "csv")
+	if args.Mode == ModeFXIntraday {
+		q.Set("interval", args.Interval)
+	}
+
+	if args.OutputSize != "" {
+		q.Set("outputsize", args.OutputSize)
+	}
+
+	body, err := c.query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return parseFXBody(args.Mode, body)
+}
+
+func parseFXBody(mode string, body io.Reader) ([]FXRate, error) {
+	records, err := readSeriesRecords(body)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := layoutStd
+	if mode == ModeFXIntraday {
+		layout = layoutIntraday
+	}
+
+	rates := make([]FXRate, 0, len(records))
+	for _, record := range records {
+		var rate FXRate
+
+		rate.Date, err = time.Parse(layout, record["timestamp"])
+		if err != nil {
+			return rates, fmt.Errorf("failed to parse timestamp %s: %w", record["timestamp"], err)
+		}
+
+		floats := []struct {
+			name string
+			dst  *float64
+		}{
+			{"open", &rate.Open},
+			{"high", &rate.High},
+			{"low", &rate.Low},
+			{"close", &rate.Close},
+		}
+		for _, f := range floats {
+			*f.dst, err = strconv.ParseFloat(record[f.name], 64)
+			if err != nil {
+				return rates, fmt.Errorf("failed to parse %s field %s: %w", f.name, record[f.name], err)
+			}
+		}
+
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
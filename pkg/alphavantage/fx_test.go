@@ -0,0 +1,170 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_GetExchangeRate(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		handler      func(logtag string, t *testing.T) http.HandlerFunc
+		expectedResp ExchangeRate
+		expectedErr  error
+	}{
+		{
+			caseName: "when currency is unknown",
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{"Error Message": "Invalid API call. Please retry or visit the documentation (https://www.alphavantage.co/documentation/) for CURRENCY_EXCHANGE_RATE."}`))
+				}
+			},
+			expectedErr: ErrInvalidSymbol,
+		},
+		{
+			caseName: "when success",
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					q := r.URL.Query()
+
+					if q.Get("function") != ModeCurrencyExchangeRate {
+						t.Errorf("%s mode [%s] is not equal [%s]", logtag, q.Get("function"), ModeCurrencyExchangeRate)
+					}
+
+					if q.Get("from_currency") != "USD" || q.Get("to_currency") != "EUR" {
+						t.Errorf("%s pair [%s/%s] is not equal [USD/EUR]", logtag, q.Get("from_currency"), q.Get("to_currency"))
+					}
+
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{
+    "Realtime Currency Exchange Rate": {
+        "1. From_Currency Code": "USD",
+        "2. From_Currency Name": "United States Dollar",
+        "3. To_Currency Code": "EUR",
+        "4. To_Currency Name": "Euro",
+        "5. Exchange Rate": "0.82030000",
+        "6. Last Refreshed": "2021-05-20 14:12:01",
+        "7. Time Zone": "UTC",
+        "8. Bid Price": "0.82020000",
+        "9. Ask Price": "0.82040000"
+    }
+}`))
+				}
+			},
+			expectedResp: ExchangeRate{
+				From:          "USD",
+				To:            "EUR",
+				Rate:          0.8203,
+				Bid:           0.8202,
+				Ask:           0.8204,
+				LastRefreshed: time.Date(2021, 5, 20, 14, 12, 1, 0, time.UTC),
+			},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		srv := httptest.NewServer(tt.handler(logTestcase, t))
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       srv.URL,
+			apiKey:     "demo",
+		}
+
+		resp, err := c.GetExchangeRate(context.Background(), "USD", "EUR")
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
+		}
+
+		srv.Close()
+	}
+}
+
+func TestClient_GetFXTimeSeries(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		args         GetFXArgs
+		body         string
+		expectedResp []FXRate
+	}{
+		{
+			caseName: "when daily",
+			args:     GetFXArgs{Mode: ModeFXDaily, From: "USD", To: "EUR", OutputSize: OutputSizeFull},
+			body: `timestamp,open,high,low,close
+2021-05-20,0.8190,0.8210,0.8170,0.8203
+2021-05-19,0.8180,0.8200,0.8160,0.8190`,
+			expectedResp: []FXRate{
+				{Open: 0.819, High: 0.821, Low: 0.817, Close: 0.8203, Date: time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC)},
+				{Open: 0.818, High: 0.82, Low: 0.816, Close: 0.819, Date: time.Date(2021, 5, 19, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			caseName: "when intraday",
+			args:     GetFXArgs{Mode: ModeFXIntraday, Interval: Interval5min, From: "USD", To: "EUR"},
+			body: `timestamp,open,high,low,close
+2021-05-20 14:05:00,0.8190,0.8210,0.8170,0.8203`,
+			expectedResp: []FXRate{
+				{Open: 0.819, High: 0.821, Low: 0.817, Close: 0.8203, Date: time.Date(2021, 5, 20, 14, 5, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+
+			if q.Get("function") != tt.args.Mode {
+				t.Errorf("%s mode [%s] is not equal [%s]", logTestcase, q.Get("function"), tt.args.Mode)
+			}
+
+			if q.Get("from_symbol") != tt.args.From || q.Get("to_symbol") != tt.args.To {
+				t.Errorf("%s pair [%s/%s] is not equal [%s/%s]", logTestcase, q.Get("from_symbol"), q.Get("to_symbol"), tt.args.From, tt.args.To)
+			}
+
+			if q.Get("interval") != tt.args.Interval {
+				t.Errorf("%s interval [%s] is not equal [%s]", logTestcase, q.Get("interval"), tt.args.Interval)
+			}
+
+			if q.Get("outputsize") != tt.args.OutputSize {
+				t.Errorf("%s outputsize [%s] is not equal [%s]", logTestcase, q.Get("outputsize"), tt.args.OutputSize)
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(tt.body))
+		}))
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       srv.URL,
+			apiKey:     "demo",
+		}
+
+		resp, err := c.GetFXTimeSeries(context.Background(), tt.args)
+		if err != nil {
+			t.Error(logTestcase, "unexpected err", err)
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
+		}
+
+		srv.Close()
+	}
+}
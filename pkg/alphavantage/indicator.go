@@ -0,0 +1,128 @@
+package alphavantage
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// the intraday indicator series leave out the seconds
+const layoutIndicatorIntraday = "2006-01-02 15:04"
+
+type IndicatorArgs struct {
+	// Function is the alphavantage name of the indicator, such as SMA or
+	// BBANDS
+	Function string
+	Symbol   string
+	// Interval is one of the intraday intervals or daily, weekly or monthly
+	Interval string
+
+	// TimePeriod and SeriesType are only sent when set, as not every
+	// function takes them
+	TimePeriod int
+	SeriesType string
+}
+
+// Indicator is the series of a technical indicator. Functions such as BBANDS
+// or STOCH have several lines, Columns lists them in the order alphavantage
+// sends them.
+type Indicator struct {
+	Columns []string
+	Points  []IndicatorPoint
+}
+
+type IndicatorPoint struct {
+	Time   time.Time
+	Values map[string]float64
+}
+
+func (c *Client) GetIndicator(ctx context.Context, args IndicatorArgs) (Indicator, error) {
+	q := url.Values{}
+	q.Set("function", args.Function)
+	q.Set("symbol", args.Symbol)
+	q.Set("interval", args.Interval)
+	q.Set("datatype", "csv")
+	if args.TimePeriod > 0 {
+		q.Set("time_period", strconv.Itoa(args.TimePeriod))
+	}
+
+	if args.SeriesType != "" {
+		q.Set("series_type", args.SeriesType)
+	}
+
+	loc, err := loadLocation(SymbolTimeZone(args.Symbol))
+	if err != nil {
+		return Indicator{}, err
+	}
+
+	body, err := c.query(ctx, q)
+	if err != nil {
+		return Indicator{}, err
+	}
+	defer body.Close()
+
+	return parseIndicatorBody(body, loc)
+}
+
+// parseIndicatorBody reads a time column followed by one column per line of
+// the indicator, intraday times are in loc.
+func parseIndicatorBody(body io.Reader, loc *time.Location) (Indicator, error) {
+	csvReader := csv.NewReader(body)
+	csvReader.LazyQuotes = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return Indicator{}, nil
+		}
+
+		return Indicator{}, fmt.Errorf("error reading csv header: %w", err)
+	}
+
+	indicator := Indicator{Columns: append([]string(nil), header[1:]...)}
+	for {
+		row, err := csvReader.Read()
+		if err != nil {
+			if err == io.EOF {
+				return indicator, nil
+			}
+
+			return indicator, fmt.Errorf("error reading csv row: %w", err)
+		}
+
+		point := IndicatorPoint{Values: make(map[string]float64, len(indicator.Columns))}
+		point.Time, err = parseIndicatorTime(row[0], loc)
+		if err != nil {
+			return indicator, err
+		}
+
+		for i, column := range indicator.Columns {
+			point.Values[column], err = strconv.ParseFloat(row[i+1], 64)
+			if err != nil {
+				return indicator, fmt.Errorf("failed to parse %s field %s: %w", column, row[i+1], err)
+			}
+		}
+
+		indicator.Points = append(indicator.Points, point)
+	}
+}
+
+// parseIndicatorTime reads a date in UTC, as series dates are, and a time of
+// day in loc.
+func parseIndicatorTime(v string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(layoutStd, v); err == nil {
+		return t, nil
+	}
+
+	for _, layout := range []string{layoutIndicatorIntraday, layoutIntraday} {
+		if t, err := time.ParseInLocation(layout, v, loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("failed to parse time %s", v)
+}
@@ -0,0 +1,105 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_GetIndicator(t *testing.T) {
+	eastern, err := loadLocation(DefaultTimeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tts = []struct {
+		caseName       string
+		args           IndicatorArgs
+		body           string
+		expectedParams map[string]string
+		expectedResp   Indicator
+		expectedErr    error
+	}{
+		{
+			caseName: "when function is invalid",
+			args:     IndicatorArgs{Function: "NOPE", Symbol: "abcde", Interval: "daily"},
+			body:     `{"Error Message": "This API function (NOPE) does not exist."}`,
+			expectedParams: map[string]string{
+				"function": "NOPE", "symbol": "abcde", "interval": "daily", "time_period": "", "series_type": "",
+			},
+			expectedErr: ErrInvalidSymbol,
+		},
+		{
+			caseName: "when single column",
+			args:     IndicatorArgs{Function: "SMA", Symbol: "abcde", Interval: "daily", TimePeriod: 20, SeriesType: "close"},
+			body: `time,SMA
+2021-05-20,143.2150
+2021-05-19,142.9870`,
+			expectedParams: map[string]string{
+				"function": "SMA", "symbol": "abcde", "interval": "daily", "time_period": "20", "series_type": "close",
+			},
+			expectedResp: Indicator{
+				Columns: []string{"SMA"},
+				Points: []IndicatorPoint{
+					{Time: time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC), Values: map[string]float64{"SMA": 143.215}},
+					{Time: time.Date(2021, 5, 19, 0, 0, 0, 0, time.UTC), Values: map[string]float64{"SMA": 142.987}},
+				},
+			},
+		},
+		{
+			caseName: "when several columns intraday",
+			args:     IndicatorArgs{Function: "STOCH", Symbol: "abcde", Interval: "5min"},
+			body: `time,SlowK,SlowD
+2021-05-20 16:00,80.5,75.25`,
+			expectedParams: map[string]string{
+				"function": "STOCH", "symbol": "abcde", "interval": "5min", "time_period": "", "series_type": "",
+			},
+			expectedResp: Indicator{
+				Columns: []string{"SlowK", "SlowD"},
+				Points: []IndicatorPoint{
+					{Time: time.Date(2021, 5, 20, 16, 0, 0, 0, eastern), Values: map[string]float64{"SlowK": 80.5, "SlowD": 75.25}},
+				},
+			},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			q := r.URL.Query()
+
+			for name, expected := range tt.expectedParams {
+				if q.Get(name) != expected {
+					t.Errorf("%s %s [%s] is not equal [%s]", logTestcase, name, q.Get(name), expected)
+				}
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(tt.body))
+		}))
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       srv.URL,
+			apiKey:     "demo",
+		}
+
+		resp, err := c.GetIndicator(context.Background(), tt.args)
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
+		}
+
+		srv.Close()
+	}
+}
@@ -0,0 +1,130 @@
+package alphavantage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+)
+
+const (
+	ModeOverview = "OVERVIEW"
+)
+
+var (
+	ErrEmptyOverview = errors.New("overview response has no data")
+)
+
+// Overview is the company profile and key figures of a symbol. Figures
+// alphavantage doesn't have, as for funds, are left zero.
+type Overview struct {
+	Symbol            string
+	Name              string
+	Exchange          string
+	Currency          string
+	Country           string
+	Sector            string
+	Industry          string
+	SharesOutstanding int64
+	PERatio           float64
+	EPS               float64
+	// DividendYield is a fraction, 0.05 means 5%
+	DividendYield float64
+	Week52High    float64
+	Week52Low     float64
+}
+
+// overviewBody is the json alphavantage sends, every value is a string and
+// missing figures are "None" or "-".
+type overviewBody struct {
+	Symbol            string `json:"Symbol"`
+	Name              string `json:"Name"`
+	Exchange          string `json:"Exchange"`
+	Currency          string `json:"Currency"`
+	Country           string `json:"Country"`
+	Sector            string `json:"Sector"`
+	Industry          string `json:"Industry"`
+	SharesOutstanding string `json:"SharesOutstanding"`
+	PERatio           string `json:"PERatio"`
+	EPS               string `json:"EPS"`
+	DividendYield     string `json:"DividendYield"`
+	Week52High        string `json:"52WeekHigh"`
+	Week52Low         string `json:"52WeekLow"`
+}
+
+// GetOverview fetches the company overview of symbol, which only comes as
+// json.
+func (c *Client) GetOverview(ctx context.Context, symbol string) (Overview, error) {
+	q := url.Values{}
+	q.Set("function", ModeOverview)
+	q.Set("symbol", symbol)
+
+	body, err := c.query(ctx, q)
+	if err != nil {
+		return Overview{}, err
+	}
+	defer body.Close()
+
+	return parseOverview(body)
+}
+
+func parseOverview(body io.Reader) (Overview, error) {
+	var raw overviewBody
+	if err := json.NewDecoder(body).Decode(&raw); err != nil {
+		return Overview{}, fmt.Errorf("failed to decode overview: %w", err)
+	}
+
+	// an unknown symbol comes back as an empty object
+	if raw.Symbol == "" {
+		return Overview{}, ErrEmptyOverview
+	}
+
+	overview := Overview{
+		Symbol:   raw.Symbol,
+		Name:     raw.Name,
+		Exchange: raw.Exchange,
+		Currency: raw.Currency,
+		Country:  raw.Country,
+		Sector:   raw.Sector,
+		Industry: raw.Industry,
+	}
+
+	var err error
+	floats := []struct {
+		name  string
+		value string
+		dst   *float64
+	}{
+		{"PERatio", raw.PERatio, &overview.PERatio},
+		{"EPS", raw.EPS, &overview.EPS},
+		{"DividendYield", raw.DividendYield, &overview.DividendYield},
+		{"52WeekHigh", raw.Week52High, &overview.Week52High},
+		{"52WeekLow", raw.Week52Low, &overview.Week52Low},
+	}
+	for _, f := range floats {
+		if missingFigure(f.value) {
+			continue
+		}
+
+		*f.dst, err = strconv.ParseFloat(f.value, 64)
+		if err != nil {
+			return Overview{}, fmt.Errorf("failed to parse %s field %s: %w", f.name, f.value, err)
+		}
+	}
+
+	if !missingFigure(raw.SharesOutstanding) {
+		overview.SharesOutstanding, err = strconv.ParseInt(raw.SharesOutstanding, 10, 64)
+		if err != nil {
+			return Overview{}, fmt.Errorf("failed to parse SharesOutstanding field %s: %w", raw.SharesOutstanding, err)
+		}
+	}
+
+	return overview, nil
+}
+
+func missingFigure(v string) bool {
+	return v == "" || v == "None" || v == "-"
+}
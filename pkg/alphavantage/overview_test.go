@@ -0,0 +1,128 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_GetOverview(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		handler      func(logtag string, t *testing.T) http.HandlerFunc
+		expectedResp Overview
+		expectedErr  error
+	}{
+		{
+			caseName: "when symbol is unknown",
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{}`))
+				}
+			},
+			expectedErr: ErrEmptyOverview,
+		},
+		{
+			caseName: "when rate limited",
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{"Note": "Thank you for using Alpha Vantage! Our standard API call frequency is 5 calls per minute."}`))
+				}
+			},
+			expectedErr: ErrRateLimited,
+		},
+		{
+			caseName: "when success",
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					q := r.URL.Query()
+
+					if q.Get("function") != ModeOverview {
+						t.Errorf("%s mode [%s] is not equal [%s]", logtag, q.Get("function"), ModeOverview)
+					}
+
+					if q.Get("symbol") != "abcde" {
+						t.Errorf("%s symbol [%s] is not equal [%s]", logtag, q.Get("symbol"), "abcde")
+					}
+
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{
+    "Symbol": "abcde",
+    "AssetType": "Common Stock",
+    "Name": "International Business Machines",
+    "Exchange": "NYSE",
+    "Currency": "USD",
+    "Country": "USA",
+    "Sector": "TECHNOLOGY",
+    "Industry": "COMPUTER & OFFICE EQUIPMENT",
+    "PERatio": "22.3",
+    "EPS": "6.23",
+    "DividendYield": "0.0476",
+    "52WeekHigh": "153.21",
+    "52WeekLow": "113.44",
+    "SharesOutstanding": "913240000",
+    "ForwardPE": "None"
+}`))
+				}
+			},
+			expectedResp: Overview{
+				Symbol:            "abcde",
+				Name:              "International Business Machines",
+				Exchange:          "NYSE",
+				Currency:          "USD",
+				Country:           "USA",
+				Sector:            "TECHNOLOGY",
+				Industry:          "COMPUTER & OFFICE EQUIPMENT",
+				SharesOutstanding: 913240000,
+				PERatio:           22.3,
+				EPS:               6.23,
+				DividendYield:     0.0476,
+				Week52High:        153.21,
+				Week52Low:         113.44,
+			},
+		},
+		{
+			caseName: "when figures are missing",
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{"Symbol": "abcde", "Name": "Some Fund", "PERatio": "None", "EPS": "-", "SharesOutstanding": "None"}`))
+				}
+			},
+			expectedResp: Overview{
+				Symbol: "abcde",
+				Name:   "Some Fund",
+			},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		srv := httptest.NewServer(tt.handler(logTestcase, t))
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       srv.URL,
+			apiKey:     "demo",
+		}
+
+		resp, err := c.GetOverview(context.Background(), "abcde")
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
+		}
+
+		srv.Close()
+	}
+}
@@ -0,0 +1,101 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ModeGlobalQuote = "GLOBAL_QUOTE"
+)
+
+var (
+	ErrEmptyQuote = errors.New("quote response has no data")
+)
+
+// Quote is the latest price snapshot of a symbol.
+type Quote struct {
+	Symbol           string
+	Open             float64
+	High             float64
+	Low              float64
+	Price            float64
+	Volume           int64
+	LatestTradingDay time.Time
+	PreviousClose    float64
+	Change           float64
+	// ChangePercent is in percent, 1.5 means 1.5%
+	ChangePercent float64
+}
+
+func (c *Client) GetGlobalQuote(ctx context.Context, symbol string) (Quote, error) {
+	q := url.Values{}
+	q.Set("function", ModeGlobalQuote)
+	q.Set("symbol", symbol)
+	q.Set("datatype", "csv")
+
+	body, err := c.query(ctx, q)
+	if err != nil {
+		return Quote{}, err
+	}
+	defer body.Close()
+
+	return parseQuote(body)
+}
+
+func parseQuote(body io.Reader) (Quote, error) {
+	records, err := readRecords(body)
+	if err != nil {
+		return Quote{}, err
+	}
+
+	// an unknown symbol comes back as a header followed by an empty row
+	if len(records) == 0 || records[0]["symbol"] == "" {
+		return Quote{}, ErrEmptyQuote
+	}
+	fields := records[0]
+
+	var quote Quote
+	quote.Symbol = fields["symbol"]
+
+	floats := []struct {
+		name string
+		dst  *float64
+	}{
+		{"open", &quote.Open},
+		{"high", &quote.High},
+		{"low", &quote.Low},
+		{"price", &quote.Price},
+		{"previousClose", &quote.PreviousClose},
+		{"change", &quote.Change},
+	}
+	for _, f := range floats {
+		*f.dst, err = strconv.ParseFloat(fields[f.name], 64)
+		if err != nil {
+			return Quote{}, fmt.Errorf("failed to parse %s field %s: %w", f.name, fields[f.name], err)
+		}
+	}
+
+	quote.ChangePercent, err = strconv.ParseFloat(strings.TrimSuffix(fields["changePercent"], "%"), 64)
+	if err != nil {
+		return Quote{}, fmt.Errorf("failed to parse changePercent field %s: %w", fields["changePercent"], err)
+	}
+
+	quote.Volume, err = strconv.ParseInt(fields["volume"], 10, 64)
+	if err != nil {
+		return Quote{}, fmt.Errorf("failed to parse volume field %s: %w", fields["volume"], err)
+	}
+
+	quote.LatestTradingDay, err = time.Parse(layoutStd, fields["latestDay"])
+	if err != nil {
+		return Quote{}, fmt.Errorf("failed to parse latestDay field %s: %w", fields["latestDay"], err)
+	}
+
+	return quote, nil
+}
@@ -0,0 +1,105 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_GetGlobalQuote(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		handler      func(logtag string, t *testing.T) http.HandlerFunc
+		expectedResp Quote
+		expectedErr  error
+	}{
+		{
+			caseName: "when error response from server",
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte("error"))
+				}
+			},
+			expectedErr: ErrServerResponse,
+		},
+		{
+			caseName: "when symbol is unknown",
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`symbol,open,high,low,price,volume,latestDay,previousClose,change,changePercent
+,,,,,,,,,`))
+				}
+			},
+			expectedErr: ErrEmptyQuote,
+		},
+		{
+			caseName: "when success",
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					q := r.URL.Query()
+
+					if q.Get("function") != ModeGlobalQuote {
+						t.Errorf("%s mode [%s] is not equal [%s]", logtag, q.Get("function"), ModeGlobalQuote)
+					}
+
+					if q.Get("symbol") != "abcde" {
+						t.Errorf("%s symbol [%s] is not equal [%s]", logtag, q.Get("symbol"), "abcde")
+					}
+
+					if q.Get("apikey") != "demo" {
+						t.Errorf("%s demo [%s] is not equal [%s]", logtag, q.Get("apikey"), "demo")
+					}
+
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`symbol,open,high,low,price,volume,latestDay,previousClose,change,changePercent
+abcde,126.0000,127.3000,125.9700,126.5900,3624561,2021-01-29,127.0600,-0.4700,-0.3699%`))
+				}
+			},
+			expectedResp: Quote{
+				Symbol:           "abcde",
+				Open:             126.00,
+				High:             127.30,
+				Low:              125.97,
+				Price:            126.59,
+				Volume:           3624561,
+				LatestTradingDay: time.Date(2021, 1, 29, 0, 0, 0, 0, time.UTC),
+				PreviousClose:    127.06,
+				Change:           -0.47,
+				ChangePercent:    -0.3699,
+			},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		srv := httptest.NewServer(tt.handler(logTestcase, t))
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       srv.URL,
+			apiKey:     "demo",
+		}
+
+		resp, err := c.GetGlobalQuote(context.Background(), "abcde")
+		if err != nil {
+			if !errors.Is(err, tt.expectedErr) {
+				t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+			}
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
+		}
+
+		srv.Close()
+	}
+}
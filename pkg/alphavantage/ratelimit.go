@@ -0,0 +1,168 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrRateLimitExceeded = errors.New("client rate limit exceeded")
+)
+
+// RateLimit is the call budget of an api key. A zero PerMinute or PerDay
+// leaves that window unlimited.
+type RateLimit struct {
+	PerMinute int
+	PerDay    int
+	// FailFast makes calls over budget return ErrRateLimitExceeded instead of
+	// waiting for the window to admit them.
+	FailFast bool
+}
+
+// Budget is the number of calls that can be made right now in each window,
+// -1 means the window is unlimited.
+type Budget struct {
+	MinuteRemaining int
+	DayRemaining    int
+}
+
+// window admits at most limit calls in any period long stretch of time. It
+// keeps the time of the calls still within the last period, a bucket refilled
+// evenly would let a full bucket and its refill through in the same period.
+type window struct {
+	limit  int
+	period time.Duration
+	calls  []time.Time
+}
+
+func newWindow(limit int, period time.Duration) *window {
+	if limit <= 0 {
+		return nil
+	}
+
+	return &window{limit: limit, period: period}
+}
+
+// prune drops the calls that left the period ending at now.
+func (w *window) prune(now time.Time) {
+	i := 0
+	for i < len(w.calls) && !now.Before(w.calls[i].Add(w.period)) {
+		i++
+	}
+
+	w.calls = w.calls[i:]
+}
+
+// wait is how long until the window admits another call.
+func (w *window) wait(now time.Time) time.Duration {
+	if len(w.calls) < w.limit {
+		return 0
+	}
+
+	return w.calls[len(w.calls)-w.limit].Add(w.period).Sub(now)
+}
+
+func (w *window) remaining() int {
+	if w == nil {
+		return -1
+	}
+
+	return w.limit - len(w.calls)
+}
+
+type rateLimiter struct {
+	mu       sync.Mutex
+	minute   *window
+	day      *window
+	failFast bool
+	now      func() time.Time
+}
+
+func newRateLimiter(limit RateLimit, now func() time.Time) *rateLimiter {
+	return &rateLimiter{
+		minute:   newWindow(limit.PerMinute, time.Minute),
+		day:      newWindow(limit.PerDay, 24*time.Hour),
+		failFast: limit.FailFast,
+		now:      now,
+	}
+}
+
+// Wait takes a call from every window, waiting until they all admit one or
+// ctx is done.
+func (r *rateLimiter) Wait(ctx context.Context) error {
+	for {
+		wait := r.take()
+		if wait == 0 {
+			return nil
+		}
+
+		if r.failFast {
+			return ErrRateLimitExceeded
+		}
+
+		// no point in waiting for a call we won't be around to make
+		if deadline, ok := ctx.Deadline(); ok && r.now().Add(wait).After(deadline) {
+			return ErrRateLimitExceeded
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// take records a call when every window admits one, otherwise it returns how
+// long to wait before trying again.
+func (r *rateLimiter) take() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+
+	var wait time.Duration
+	for _, w := range []*window{r.minute, r.day} {
+		if w == nil {
+			continue
+		}
+
+		w.prune(now)
+		if d := w.wait(now); d > wait {
+			wait = d
+		}
+	}
+
+	if wait > 0 {
+		return wait
+	}
+
+	for _, w := range []*window{r.minute, r.day} {
+		if w != nil {
+			w.calls = append(w.calls, now)
+		}
+	}
+
+	return 0
+}
+
+func (r *rateLimiter) Budget() Budget {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for _, w := range []*window{r.minute, r.day} {
+		if w != nil {
+			w.prune(now)
+		}
+	}
+
+	return Budget{
+		MinuteRemaining: r.minute.remaining(),
+		DayRemaining:    r.day.remaining(),
+	}
+}
@@ -0,0 +1,178 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	return f.now
+}
+
+func TestRateLimiter_FailFast(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2020, 11, 6, 20, 0, 0, 0, time.UTC)}
+	limiter := newRateLimiter(RateLimit{PerMinute: 2, PerDay: 3, FailFast: true}, clock.Now)
+
+	var tts = []struct {
+		caseName       string
+		advance        time.Duration
+		expectedErr    error
+		expectedBudget Budget
+	}{
+		{
+			caseName:       "first call within budget",
+			expectedErr:    nil,
+			expectedBudget: Budget{MinuteRemaining: 1, DayRemaining: 2},
+		},
+		{
+			caseName:       "second call within budget",
+			expectedErr:    nil,
+			expectedBudget: Budget{MinuteRemaining: 0, DayRemaining: 1},
+		},
+		{
+			caseName:       "minute budget exhausted",
+			expectedErr:    ErrRateLimitExceeded,
+			expectedBudget: Budget{MinuteRemaining: 0, DayRemaining: 1},
+		},
+		{
+			caseName:       "minute budget refilled",
+			advance:        time.Minute,
+			expectedErr:    nil,
+			expectedBudget: Budget{MinuteRemaining: 1, DayRemaining: 0},
+		},
+		{
+			caseName:       "day budget exhausted",
+			advance:        time.Minute,
+			expectedErr:    ErrRateLimitExceeded,
+			expectedBudget: Budget{MinuteRemaining: 2, DayRemaining: 0},
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		clock.now = clock.now.Add(tt.advance)
+
+		err := limiter.Wait(context.Background())
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if budget := limiter.Budget(); budget != tt.expectedBudget {
+			t.Errorf("%s budget %+v not equal expected %+v", logTestcase, budget, tt.expectedBudget)
+		}
+	}
+}
+
+func TestRateLimiter_RollingWindows(t *testing.T) {
+	start := time.Date(2020, 11, 6, 20, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: start}
+	limiter := newRateLimiter(RateLimit{PerMinute: 5, PerDay: 25, FailFast: true}, clock.Now)
+
+	// a call is tried every 10 seconds for three days
+	var calls []time.Time
+	for clock.now.Before(start.Add(72 * time.Hour)) {
+		if err := limiter.Wait(context.Background()); err == nil {
+			calls = append(calls, clock.now)
+		}
+
+		clock.now = clock.now.Add(10 * time.Second)
+	}
+
+	var tts = []struct {
+		caseName string
+		period   time.Duration
+		limit    int
+	}{
+		{caseName: "minute", period: time.Minute, limit: 5},
+		{caseName: "day", period: 24 * time.Hour, limit: 25},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		for i, from := range calls {
+			count := 0
+			for _, c := range calls[i:] {
+				if c.Sub(from) < tt.period {
+					count++
+				}
+			}
+
+			if count > tt.limit {
+				t.Errorf("%s %d calls in the window from %s, limit %d", logTestcase, count, from, tt.limit)
+				break
+			}
+		}
+	}
+
+	if len(calls) != 3*25 {
+		t.Error("expected the whole day budget used every day, calls:", len(calls))
+	}
+}
+
+func TestRateLimiter_WaitCancelled(t *testing.T) {
+	limiter := newRateLimiter(RateLimit{PerMinute: 1}, time.Now)
+
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Error("expected err:", context.Canceled, ", is not err:", err)
+	}
+
+	// the call won't leave the window before the deadline so there is no point waiting
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Error("expected err:", ErrRateLimitExceeded, ", is not err:", err)
+	}
+}
+
+func TestClient_RateLimited(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`timestamp,open,high,low,close,volume
+2020-11-06,114.4400,114.4400,114.4400,114.4400,457`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(http.DefaultClient, srv.URL, "demo", WithRateLimit(RateLimit{PerMinute: 1, FailFast: true}))
+
+	args := GetStockArgs{Mode: ModeTimeSeriesDaily, Symbol: "abcde"}
+	if _, err := c.GetStockTimeSeries(context.Background(), args); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.GetStockTimeSeries(context.Background(), args); !errors.Is(err, ErrRateLimitExceeded) {
+		t.Error("expected err:", ErrRateLimitExceeded, ", is not err:", err)
+	}
+
+	if calls != 1 {
+		t.Error("expected upstream calls: 1, not equal:", calls)
+	}
+
+	budget, ok := c.RemainingBudget()
+	if !ok || budget.MinuteRemaining != 0 || budget.DayRemaining != -1 {
+		t.Errorf("unexpected budget %+v, limited %v", budget, ok)
+	}
+}
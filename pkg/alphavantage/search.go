@@ -0,0 +1,71 @@
+package alphavantage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+)
+
+const (
+	ModeSymbolSearch = "SYMBOL_SEARCH"
+)
+
+// SymbolMatch is a single result of a symbol search.
+type SymbolMatch struct {
+	Symbol      string
+	Name        string
+	Type        string
+	Region      string
+	MarketOpen  string
+	MarketClose string
+	Timezone    string
+	Currency    string
+	// MatchScore goes from 0 to 1, 1 being an exact match
+	MatchScore float64
+}
+
+func (c *Client) SearchSymbols(ctx context.Context, keywords string) ([]SymbolMatch, error) {
+	q := url.Values{}
+	q.Set("function", ModeSymbolSearch)
+	q.Set("keywords", keywords)
+	q.Set("datatype", "csv")
+
+	body, err := c.query(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return parseSymbolMatches(body)
+}
+
+func parseSymbolMatches(body io.Reader) ([]SymbolMatch, error) {
+	records, err := readRecords(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []SymbolMatch
+	for _, record := range records {
+		score, err := strconv.ParseFloat(record["matchScore"], 64)
+		if err != nil {
+			return matches, fmt.Errorf("failed to parse matchScore field %s: %w", record["matchScore"], err)
+		}
+
+		matches = append(matches, SymbolMatch{
+			Symbol:      record["symbol"],
+			Name:        record["name"],
+			Type:        record["type"],
+			Region:      record["region"],
+			MarketOpen:  record["marketOpen"],
+			MarketClose: record["marketClose"],
+			Timezone:    record["timezone"],
+			Currency:    record["currency"],
+			MatchScore:  score,
+		})
+	}
+
+	return matches, nil
+}
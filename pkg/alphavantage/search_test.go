@@ -0,0 +1,119 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClient_SearchSymbols(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		handler      func(logtag string, t *testing.T) http.HandlerFunc
+		expectedResp []SymbolMatch
+		expectedErr  error
+	}{
+		{
+			caseName: "when error response from server",
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusBadRequest)
+					w.Write([]byte("error"))
+				}
+			},
+			expectedResp: nil,
+			expectedErr:  ErrServerResponse,
+		},
+		{
+			caseName: "when nothing matches",
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`symbol,name,type,region,marketOpen,marketClose,timezone,currency,matchScore`))
+				}
+			},
+			expectedResp: nil,
+			expectedErr:  nil,
+		},
+		{
+			caseName: "when success",
+			handler: func(logtag string, t *testing.T) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					q := r.URL.Query()
+
+					if q.Get("function") != ModeSymbolSearch {
+						t.Errorf("%s mode [%s] is not equal [%s]", logtag, q.Get("function"), ModeSymbolSearch)
+					}
+
+					if q.Get("keywords") != "tesco" {
+						t.Errorf("%s keywords [%s] is not equal [%s]", logtag, q.Get("keywords"), "tesco")
+					}
+
+					if q.Get("apikey") != "demo" {
+						t.Errorf("%s demo [%s] is not equal [%s]", logtag, q.Get("apikey"), "demo")
+					}
+
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`symbol,name,type,region,marketOpen,marketClose,timezone,currency,matchScore
+TSCO.LON,Tesco PLC,Equity,United Kingdom,08:00,16:30,UTC+01,GBX,0.7273
+TSCDF,Tesco plc,Equity,United States,09:30,16:00,UTC-04,USD,0.7143`))
+				}
+			},
+			expectedResp: []SymbolMatch{
+				{
+					Symbol:      "TSCO.LON",
+					Name:        "Tesco PLC",
+					Type:        "Equity",
+					Region:      "United Kingdom",
+					MarketOpen:  "08:00",
+					MarketClose: "16:30",
+					Timezone:    "UTC+01",
+					Currency:    "GBX",
+					MatchScore:  0.7273,
+				},
+				{
+					Symbol:      "TSCDF",
+					Name:        "Tesco plc",
+					Type:        "Equity",
+					Region:      "United States",
+					MarketOpen:  "09:30",
+					MarketClose: "16:00",
+					Timezone:    "UTC-04",
+					Currency:    "USD",
+					MatchScore:  0.7143,
+				},
+			},
+			expectedErr: nil,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		srv := httptest.NewServer(tt.handler(logTestcase, t))
+
+		c := Client{
+			httpClient: http.DefaultClient,
+			host:       srv.URL,
+			apiKey:     "demo",
+		}
+
+		resp, err := c.SearchSymbols(context.Background(), "tesco")
+		if err != nil {
+			if !errors.Is(err, tt.expectedErr) {
+				t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+			}
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
+		}
+
+		srv.Close()
+	}
+}
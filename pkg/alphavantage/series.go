@@ -0,0 +1,297 @@
+package alphavantage
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrShortRow      = errors.New("row has fewer columns than the header")
+	ErrMissingColumn = errors.New("series has no such column")
+)
+
+// seriesColumns are the columns every csv time series has, adjusted or not.
+var seriesColumns = []string{"timestamp", "open", "high", "low", "close", "volume"}
+
+// ParseError is a row of a csv series, or its header, that can't be read.
+// Line counts csv records from 1 at the header rather than physical lines,
+// encoding/csv skips blank lines without counting them. Alphavantage sends
+// neither blank lines nor quoted line breaks, so on its bodies the two agree.
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// SeriesReader reads the points of a csv time series one row at a time, so a
+// full series doesn't have to be held in memory. Columns are found by their
+// header name and unknown ones are skipped.
+type SeriesReader struct {
+	csvReader *csv.Reader
+	closer    io.Closer
+	header    []string
+	layout    string
+	loc       *time.Location
+	line      int
+}
+
+// NewSeriesReader reads a series of mode from body. Intraday timestamps are
+// read in loc, UTC when it is nil, and dates always in UTC.
+func NewSeriesReader(mode string, loc *time.Location, body io.Reader) (*SeriesReader, error) {
+	csvReader := csv.NewReader(body)
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1
+	csvReader.ReuseRecord = true
+
+	r := &SeriesReader{csvReader: csvReader, layout: layoutStd, loc: time.UTC, line: 1}
+	if mode == ModeTimeSeriesIntraday {
+		r.layout = layoutIntraday
+		if loc != nil {
+			r.loc = loc
+		}
+	}
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return r, nil
+	}
+
+	if err != nil {
+		return nil, &ParseError{Line: 1, Err: fmt.Errorf("error reading csv header: %w", err)}
+	}
+	r.header = append([]string(nil), header...)
+
+	columns := make(map[string]string, len(r.header))
+	for _, name := range r.header {
+		columns[name] = name
+	}
+
+	for _, name := range seriesColumns {
+		if _, ok := seriesField(columns, name); !ok {
+			return nil, &ParseError{Line: 1, Err: fmt.Errorf("%w: %s", ErrMissingColumn, name)}
+		}
+	}
+
+	return r, nil
+}
+
+// Next returns the next point of the series, io.EOF after the last one. Rows
+// that can't be read fail with a *ParseError.
+func (r *SeriesReader) Next() (Stock, error) {
+	if len(r.header) == 0 {
+		return Stock{}, io.EOF
+	}
+
+	row, err := r.csvReader.Read()
+	if err == io.EOF {
+		return Stock{}, io.EOF
+	}
+	r.line++
+
+	if err != nil {
+		return Stock{}, &ParseError{Line: r.line, Err: fmt.Errorf("error reading csv row: %w", err)}
+	}
+
+	if len(row) < len(r.header) {
+		return Stock{}, &ParseError{Line: r.line, Err: fmt.Errorf("%d of %d columns: %w", len(row), len(r.header), ErrShortRow)}
+	}
+
+	record := make(map[string]string, len(r.header))
+	for i, name := range r.header {
+		record[name] = row[i]
+	}
+
+	stock, err := parseSeriesRecord(r.layout, r.loc, record)
+	if err != nil {
+		return Stock{}, &ParseError{Line: r.line, Err: err}
+	}
+
+	return stock, nil
+}
+
+// Close closes the body the reader was opened on by OpenStockTimeSeries.
+func (r *SeriesReader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+
+	return r.closer.Close()
+}
+
+// OpenStockTimeSeries fetches a csv time series to be read point by point, the
+// caller must close the reader. It is meant for full series too large to
+// parse at once.
+func (c *Client) OpenStockTimeSeries(ctx context.Context, args GetStockArgs) (*SeriesReader, error) {
+	loc, err := seriesLocation(args.Mode, SymbolTimeZone(args.Symbol))
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.query(ctx, seriesQuery(args, DataTypeCSV))
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := NewSeriesReader(args.Mode, loc, body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	r.closer = body
+
+	return r, nil
+}
+
+// seriesField returns the column name of a row, the adjusted weekly and
+// monthly series spell their multi word columns with spaces.
+func seriesField(record map[string]string, name string) (string, bool) {
+	if v, ok := record[name]; ok {
+		return v, true
+	}
+
+	v, ok := record[strings.ReplaceAll(name, "_", " ")]
+	return v, ok
+}
+
+// parseBody reads a whole csv time series, adjusted series and their extra
+// columns parse the same way as the plain ones.
+func parseBody(mode string, loc *time.Location, body io.Reader) ([]Stock, error) {
+	r, err := NewSeriesReader(mode, loc, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var stocks []Stock
+	for {
+		stock, err := r.Next()
+		if err == io.EOF {
+			return stocks, nil
+		}
+
+		if err != nil {
+			return stocks, err
+		}
+
+		stocks = append(stocks, stock)
+	}
+}
+
+// readSeriesRecords reads a whole csv body into rows keyed by column name,
+// rows shorter than the header fail with a *ParseError as in a SeriesReader.
+func readSeriesRecords(body io.Reader) ([]map[string]string, error) {
+	csvReader := csv.NewReader(body)
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, &ParseError{Line: 1, Err: fmt.Errorf("error reading csv header: %w", err)}
+	}
+
+	var records []map[string]string
+	for line := 2; ; line++ {
+		row, err := csvReader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+
+		if err != nil {
+			return records, &ParseError{Line: line, Err: fmt.Errorf("error reading csv row: %w", err)}
+		}
+
+		if len(row) < len(header) {
+			return records, &ParseError{Line: line, Err: fmt.Errorf("%d of %d columns: %w", len(row), len(header), ErrShortRow)}
+		}
+
+		record := make(map[string]string, len(header))
+		for i, name := range header {
+			record[name] = row[i]
+		}
+
+		records = append(records, record)
+	}
+}
+
+// parseRecords reads the points of a time series from rows keyed by column
+// name, intraday timestamps in loc.
+func parseRecords(mode string, loc *time.Location, records []map[string]string) ([]Stock, error) {
+	layout := layoutStd
+	if mode == ModeTimeSeriesIntraday {
+		layout = layoutIntraday
+	} else {
+		loc = time.UTC
+	}
+
+	stocks := make([]Stock, 0, len(records))
+	for _, record := range records {
+		stock, err := parseSeriesRecord(layout, loc, record)
+		if err != nil {
+			return stocks, err
+		}
+
+		stocks = append(stocks, stock)
+	}
+
+	return stocks, nil
+}
+
+func parseSeriesRecord(layout string, loc *time.Location, record map[string]string) (Stock, error) {
+	var stock Stock
+	var err error
+
+	timestamp, _ := seriesField(record, "timestamp")
+	stock.Date, err = time.ParseInLocation(layout, timestamp, loc)
+	if err != nil {
+		return Stock{}, fmt.Errorf("failed to parse timestamp %s: %w", timestamp, err)
+	}
+
+	floats := []struct {
+		name     string
+		dst      *float64
+		optional bool
+	}{
+		{name: "open", dst: &stock.Open},
+		{name: "high", dst: &stock.High},
+		{name: "low", dst: &stock.Low},
+		{name: "close", dst: &stock.Close},
+		{name: "adjusted_close", dst: &stock.AdjustedClose, optional: true},
+		{name: "dividend_amount", dst: &stock.DividendAmount, optional: true},
+		{name: "split_coefficient", dst: &stock.SplitCoefficient, optional: true},
+	}
+	for _, f := range floats {
+		v, ok := seriesField(record, f.name)
+		if !ok && f.optional {
+			continue
+		}
+
+		*f.dst, err = strconv.ParseFloat(v, 64)
+		if err != nil {
+			return Stock{}, fmt.Errorf("failed to parse %s field %s: %w", f.name, v, err)
+		}
+	}
+
+	volume, _ := seriesField(record, "volume")
+	stock.Volume, err = strconv.ParseInt(volume, 10, 64)
+	if err != nil {
+		return Stock{}, fmt.Errorf("failed to parse volume field %s: %w", volume, err)
+	}
+
+	return stock, nil
+}
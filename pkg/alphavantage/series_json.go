@@ -0,0 +1,148 @@
+package alphavantage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrEmptySeries = errors.New("series response has no data")
+)
+
+// Series is a time series with the meta data alphavantage sends along in
+// json, a csv series only fills Stocks.
+type Series struct {
+	Meta   MetaData
+	Stocks []Stock
+}
+
+// MetaData describes a series. Interval is only sent for intraday series and
+// OutputSize for the ones that can be compact.
+type MetaData struct {
+	Information   string
+	Symbol        string
+	LastRefreshed time.Time
+	Interval      string
+	OutputSize    string
+	// TimeZone is the IANA name of the zone the timestamps are in, such as
+	// US/Eastern
+	TimeZone string
+}
+
+// fieldNumber is the "1. " alphavantage puts before every json key.
+var fieldNumber = regexp.MustCompile(`^\d+[a-z]?\. `)
+
+// jsonField turns a numbered json key such as "5. adjusted close" into the
+// column name csv has for it, adjusted_close.
+func jsonField(key string) string {
+	return strings.ReplaceAll(fieldNumber.ReplaceAllString(key, ""), " ", "_")
+}
+
+// parseJSONBody reads the "Meta Data" object and the one time series object
+// of a json body, whose key differs per function. zone is the time zone of
+// the series when the meta data leaves it out.
+func parseJSONBody(mode, zone string, body io.Reader) (Series, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(body).Decode(&raw); err != nil {
+		return Series{}, fmt.Errorf("failed to decode series: %w", err)
+	}
+
+	var series Series
+	var meta map[string]string
+	var points map[string]map[string]string
+	for key, value := range raw {
+		if key == "Meta Data" {
+			if err := json.Unmarshal(value, &meta); err != nil {
+				return Series{}, fmt.Errorf("failed to decode meta data: %w", err)
+			}
+
+			continue
+		}
+
+		if strings.Contains(key, "Time Series") {
+			if err := json.Unmarshal(value, &points); err != nil {
+				return Series{}, fmt.Errorf("failed to decode %s: %w", key, err)
+			}
+		}
+	}
+
+	if points == nil {
+		return Series{}, ErrEmptySeries
+	}
+
+	var err error
+	if series.Meta, err = parseMetaData(meta, zone); err != nil {
+		return Series{}, err
+	}
+
+	loc, err := seriesLocation(mode, series.Meta.TimeZone)
+	if err != nil {
+		return Series{}, err
+	}
+
+	// newest first, as csv sends them
+	timestamps := make([]string, 0, len(points))
+	for timestamp := range points {
+		timestamps = append(timestamps, timestamp)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(timestamps)))
+
+	records := make([]map[string]string, 0, len(points))
+	for _, timestamp := range timestamps {
+		record := map[string]string{"timestamp": timestamp}
+		for key, value := range points[timestamp] {
+			record[jsonField(key)] = value
+		}
+
+		records = append(records, record)
+	}
+
+	series.Stocks, err = parseRecords(mode, loc, records)
+	return series, err
+}
+
+// parseMetaData reads the meta data of a series, TimeZone is zone when it is
+// not sent. Like the points, a last refresh with a time of day is in that
+// zone.
+func parseMetaData(raw map[string]string, zone string) (MetaData, error) {
+	fields := make(map[string]string, len(raw))
+	for key, value := range raw {
+		fields[jsonField(key)] = value
+	}
+
+	meta := MetaData{
+		Information: fields["Information"],
+		Symbol:      fields["Symbol"],
+		Interval:    fields["Interval"],
+		OutputSize:  fields["Output_Size"],
+		TimeZone:    fields["Time_Zone"],
+	}
+	if meta.TimeZone == "" {
+		meta.TimeZone = zone
+	}
+
+	if v := fields["Last_Refreshed"]; v != "" {
+		layout, mode := layoutStd, ""
+		if len(v) > len(layoutStd) {
+			layout, mode = layoutIntraday, ModeTimeSeriesIntraday
+		}
+
+		loc, err := seriesLocation(mode, meta.TimeZone)
+		if err != nil {
+			return MetaData{}, err
+		}
+
+		meta.LastRefreshed, err = time.ParseInLocation(layout, v, loc)
+		if err != nil {
+			return MetaData{}, fmt.Errorf("failed to parse last refreshed %s: %w", v, err)
+		}
+	}
+
+	return meta, nil
+}
@@ -0,0 +1,179 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_GetStockSeriesJSON(t *testing.T) {
+	eastern, err := loadLocation(DefaultTimeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tts = []struct {
+		caseName     string
+		args         GetStockArgs
+		body         string
+		expectedResp Series
+		expectedErr  error
+	}{
+		{
+			caseName: "when intraday",
+			args:     GetStockArgs{Mode: ModeTimeSeriesIntraday, Interval: Interval5min, Symbol: "abcde"},
+			body: `{
+    "Meta Data": {
+        "1. Information": "Intraday (5min) open, high, low, close prices and volume",
+        "2. Symbol": "abcde",
+        "3. Last Refreshed": "2021-05-20 19:55:00",
+        "4. Interval": "5min",
+        "5. Output Size": "Compact",
+        "6. Time Zone": "US/Eastern"
+    },
+    "Time Series (5min)": {
+        "2021-05-20 19:50:00": {"1. open": "144.1000", "2. high": "144.2000", "3. low": "144.0000", "4. close": "144.0500", "5. volume": "300"},
+        "2021-05-20 19:55:00": {"1. open": "144.0500", "2. high": "144.3000", "3. low": "144.0500", "4. close": "144.2000", "5. volume": "1024"}
+    }
+}`,
+			expectedResp: Series{
+				Meta: MetaData{
+					Information:   "Intraday (5min) open, high, low, close prices and volume",
+					Symbol:        "abcde",
+					LastRefreshed: time.Date(2021, 5, 20, 19, 55, 0, 0, eastern),
+					Interval:      "5min",
+					OutputSize:    "Compact",
+					TimeZone:      "US/Eastern",
+				},
+				Stocks: []Stock{
+					{Open: 144.05, High: 144.3, Low: 144.05, Close: 144.2, Volume: 1024, Date: time.Date(2021, 5, 20, 19, 55, 0, 0, eastern)},
+					{Open: 144.1, High: 144.2, Low: 144, Close: 144.05, Volume: 300, Date: time.Date(2021, 5, 20, 19, 50, 0, 0, eastern)},
+				},
+			},
+		},
+		{
+			caseName: "when weekly adjusted",
+			args:     GetStockArgs{Mode: ModeTimeSeriesWeeklyAdjusted, Symbol: "abcde"},
+			body: `{
+    "Meta Data": {
+        "1. Information": "Weekly Adjusted Prices and Volumes",
+        "2. Symbol": "abcde",
+        "3. Last Refreshed": "2020-11-06",
+        "4. Time Zone": "US/Eastern"
+    },
+    "Weekly Adjusted Time Series": {
+        "2020-11-06": {"1. open": "114.44", "2. high": "116.0", "3. low": "113.0", "4. close": "114.04", "5. adjusted close": "112.4", "6. volume": "4573000", "7. dividend amount": "1.63"}
+    }
+}`,
+			expectedResp: Series{
+				Meta: MetaData{
+					Information:   "Weekly Adjusted Prices and Volumes",
+					Symbol:        "abcde",
+					LastRefreshed: time.Date(2020, 11, 6, 0, 0, 0, 0, time.UTC),
+					TimeZone:      "US/Eastern",
+				},
+				Stocks: []Stock{{
+					Open: 114.44, High: 116, Low: 113, Close: 114.04, Volume: 4573000,
+					Date:          time.Date(2020, 11, 6, 0, 0, 0, 0, time.UTC),
+					AdjustedClose: 112.4, DividendAmount: 1.63,
+				}},
+			},
+		},
+		{
+			caseName: "when meta data names the zone",
+			args:     GetStockArgs{Mode: ModeTimeSeriesIntraday, Interval: Interval60min, Symbol: "abcde"},
+			body: `{
+    "Meta Data": {
+        "1. Information": "Intraday (60min) open, high, low, close prices and volume",
+        "2. Symbol": "abcde",
+        "3. Last Refreshed": "2021-05-20 16:00:00",
+        "4. Interval": "60min",
+        "5. Output Size": "Compact",
+        "6. Time Zone": "UTC"
+    },
+    "Time Series (60min)": {
+        "2021-05-20 16:00:00": {"1. open": "144.1000", "2. high": "144.2000", "3. low": "144.0000", "4. close": "144.0500", "5. volume": "300"}
+    }
+}`,
+			expectedResp: Series{
+				Meta: MetaData{
+					Information:   "Intraday (60min) open, high, low, close prices and volume",
+					Symbol:        "abcde",
+					LastRefreshed: time.Date(2021, 5, 20, 16, 0, 0, 0, time.UTC),
+					Interval:      "60min",
+					OutputSize:    "Compact",
+					TimeZone:      "UTC",
+				},
+				Stocks: []Stock{
+					{Open: 144.1, High: 144.2, Low: 144, Close: 144.05, Volume: 300, Date: time.Date(2021, 5, 20, 16, 0, 0, 0, time.UTC)},
+				},
+			},
+		},
+		{
+			caseName:    "when series is missing",
+			args:        GetStockArgs{Mode: ModeTimeSeriesDaily, Symbol: "abcde"},
+			body:        `{}`,
+			expectedErr: ErrEmptySeries,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("datatype") != DataTypeJSON {
+				t.Errorf("%s datatype [%s] is not equal [%s]", logTestcase, r.URL.Query().Get("datatype"), DataTypeJSON)
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(tt.body))
+		}))
+
+		c := NewClient(http.DefaultClient, srv.URL, "demo", WithDataType("Json"))
+
+		resp, err := c.GetStockSeries(context.Background(), tt.args)
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received value %+v not equal expected value %+v", logTestcase, resp, tt.expectedResp)
+		}
+
+		srv.Close()
+	}
+}
+
+func TestParseDataType(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		dataType     string
+		expectedResp string
+		expectedErr  error
+	}{
+		{caseName: "when left out", dataType: "", expectedResp: DataTypeCSV},
+		{caseName: "when upper case", dataType: " JSON", expectedResp: DataTypeJSON},
+		{caseName: "when csv", dataType: "csv", expectedResp: DataTypeCSV},
+		{caseName: "when unknown", dataType: "xml", expectedErr: ErrUnknownDataType},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		resp, err := ParseDataType(tt.dataType)
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		if resp != tt.expectedResp {
+			t.Errorf("%s data type [%s] not equal expected [%s]", logTestcase, resp, tt.expectedResp)
+		}
+	}
+}
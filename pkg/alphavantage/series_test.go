@@ -0,0 +1,167 @@
+package alphavantage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBodyByHeader(t *testing.T) {
+	var tts = []struct {
+		caseName     string
+		body         string
+		expectedResp []Stock
+		expectedLine int
+		expectedErr  error
+	}{
+		{
+			caseName: "when columns are reordered and extra",
+			body: `volume,close,timestamp,vwap,low,high,open
+100,1.5,2021-05-20,1.4,1.0,2.0,1.2`,
+			expectedResp: []Stock{
+				{Open: 1.2, High: 2, Low: 1, Close: 1.5, Volume: 100, Date: time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+		{
+			caseName: "when a row is short",
+			body: `timestamp,open,high,low,close,volume
+2021-05-20,1.2,2.0,1.0,1.5,100
+2021-05-19,1.2,2.0`,
+			expectedResp: []Stock{
+				{Open: 1.2, High: 2, Low: 1, Close: 1.5, Volume: 100, Date: time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC)},
+			},
+			expectedLine: 3,
+			expectedErr:  ErrShortRow,
+		},
+		{
+			caseName: "when a column is missing",
+			body: `timestamp,open,high,low,volume
+2021-05-20,1.2,2.0,1.0,100`,
+			expectedLine: 1,
+			expectedErr:  ErrMissingColumn,
+		},
+		{
+			caseName: "when a figure is malformed",
+			body: `timestamp,open,high,low,close,volume
+2021-05-20,1.2,2.0,1.0,abc,100`,
+			expectedLine: 2,
+			expectedErr:  strconv.ErrSyntax,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		resp, err := parseBody(ModeTimeSeriesDaily, nil, strings.NewReader(tt.body))
+		if !errors.Is(err, tt.expectedErr) {
+			t.Error(logTestcase, "expected err:", tt.expectedErr, ", is not err:", err)
+		}
+
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) && parseErr.Line != tt.expectedLine {
+			t.Errorf("%s error line [%d] not equal expected [%d]", logTestcase, parseErr.Line, tt.expectedLine)
+		}
+
+		if !reflect.DeepEqual(resp, tt.expectedResp) {
+			t.Errorf("%s received %+v not equal expected %+v", logTestcase, resp, tt.expectedResp)
+		}
+	}
+}
+
+func TestParseShortRows(t *testing.T) {
+	var tts = []struct {
+		caseName string
+		parse    func(io.Reader) error
+		body     string
+	}{
+		{
+			caseName: "when a crypto row is short",
+			parse: func(body io.Reader) error {
+				_, err := parseCryptoBody(ModeDigitalCurrencyDaily, "USD", body)
+				return err
+			},
+			body: `timestamp,open,high,low,close,volume
+2021-05-20,1.2,2.0,1.0,1.5,100
+2021-05-19,1.2,2.0,1.0,1.5`,
+		},
+		{
+			caseName: "when an exchange rate row is short",
+			parse: func(body io.Reader) error {
+				_, err := parseFXBody(ModeFXDaily, body)
+				return err
+			},
+			body: `timestamp,open,high,low,close
+2021-05-20,0.8190,0.8210,0.8170,0.8203
+2021-05-19,0.8180,0.8200,0.8160`,
+		},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.caseName)
+
+		err := tt.parse(strings.NewReader(tt.body))
+		if !errors.Is(err, ErrShortRow) {
+			t.Error(logTestcase, "expected err:", ErrShortRow, ", is not err:", err)
+		}
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Line != 3 {
+			t.Errorf("%s expected a parse error at line 3, got %v", logTestcase, err)
+		}
+	}
+}
+
+func TestClient_OpenStockTimeSeries(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("outputsize") != OutputSizeFull {
+			t.Errorf("outputsize [%s] is not equal [%s]", r.URL.Query().Get("outputsize"), OutputSizeFull)
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("timestamp,open,high,low,close,volume\n"))
+		for i := 0; i < 1000; i++ {
+			day := time.Date(2021, 5, 20, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -i)
+			fmt.Fprintf(w, "%s,1.0,2.0,0.5,1.5,%d\n", day.Format(layoutStd), i)
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(http.DefaultClient, srv.URL, "demo")
+
+	r, err := c.OpenStockTimeSeries(context.Background(), GetStockArgs{Mode: ModeTimeSeriesDaily, Symbol: "abcde", OutputSize: OutputSizeFull})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	var count int
+	for {
+		stock, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if stock.Volume != int64(count) {
+			t.Errorf("point %d volume [%d] not equal expected [%d]", count, stock.Volume, count)
+		}
+		count++
+	}
+
+	if count != 1000 {
+		t.Error("expected points: 1000, not equal:", count)
+	}
+}
@@ -0,0 +1,66 @@
+package alphavantage
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTimeZone is the zone alphavantage stamps the series of US listed
+// symbols in.
+const DefaultTimeZone = "US/Eastern"
+
+// exchangeTimeZones are the zones of the exchanges a symbol suffix such as
+// the LON of TSCO.LON names.
+var exchangeTimeZones = map[string]string{
+	"LON": "Europe/London",
+	"TRT": "America/Toronto",
+	"TRV": "America/Toronto",
+	"DEX": "Europe/Berlin",
+	"FRK": "Europe/Berlin",
+	"BSE": "Asia/Kolkata",
+	"SHH": "Asia/Shanghai",
+	"SHZ": "Asia/Shanghai",
+}
+
+// SymbolTimeZone returns the zone the intraday series of symbol are stamped
+// in, DefaultTimeZone unless its suffix names another exchange.
+func SymbolTimeZone(symbol string) string {
+	if i := strings.LastIndex(symbol, "."); i >= 0 {
+		if zone, ok := exchangeTimeZones[strings.ToUpper(symbol[i+1:])]; ok {
+			return zone
+		}
+	}
+
+	return DefaultTimeZone
+}
+
+var locations sync.Map
+
+// loadLocation is time.LoadLocation kept for the life of the process, so the
+// zone database is read once per zone.
+func loadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load time zone %s: %w", name, err)
+	}
+
+	actual, _ := locations.LoadOrStore(name, loc)
+	return actual.(*time.Location), nil
+}
+
+// seriesLocation returns where the timestamps of a series of mode are read
+// in. Only intraday ones carry a time of day, the others are dates and stay
+// at midnight UTC so a day reads the same in any zone.
+func seriesLocation(mode, zone string) (*time.Location, error) {
+	if mode != ModeTimeSeriesIntraday {
+		return time.UTC, nil
+	}
+
+	return loadLocation(zone)
+}
@@ -0,0 +1,64 @@
+package alphavantage
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSymbolTimeZone(t *testing.T) {
+	var tts = []struct {
+		symbol       string
+		expectedZone string
+	}{
+		{symbol: "IBM", expectedZone: DefaultTimeZone},
+		{symbol: "BRK.B", expectedZone: DefaultTimeZone},
+		{symbol: "TSCO.LON", expectedZone: "Europe/London"},
+		{symbol: "shop.trt", expectedZone: "America/Toronto"},
+		{symbol: "600104.SHH", expectedZone: "Asia/Shanghai"},
+	}
+
+	for idx, tt := range tts {
+		logTestcase := fmt.Sprintf("[TESTCASE %d]", idx)
+		t.Log(logTestcase, tt.symbol)
+
+		if zone := SymbolTimeZone(tt.symbol); zone != tt.expectedZone {
+			t.Errorf("%s zone [%s] not equal expected [%s]", logTestcase, zone, tt.expectedZone)
+		}
+
+		if _, err := loadLocation(SymbolTimeZone(tt.symbol)); err != nil {
+			t.Error(logTestcase, "unexpected err", err)
+		}
+	}
+}
+
+func TestParseBodyAcrossDST(t *testing.T) {
+	loc, err := seriesLocation(ModeTimeSeriesIntraday, DefaultTimeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// daylight saving ended on the night of 2020-11-01 in New York
+	resp, err := parseBody(ModeTimeSeriesIntraday, loc, strings.NewReader(`timestamp,open,high,low,close,volume
+2020-11-02 09:30:00,1.0,1.0,1.0,1.0,1
+2020-10-30 09:30:00,1.0,1.0,1.0,1.0,1`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []time.Time{
+		time.Date(2020, 11, 2, 14, 30, 0, 0, time.UTC),
+		time.Date(2020, 10, 30, 13, 30, 0, 0, time.UTC),
+	}
+
+	if len(resp) != len(expected) {
+		t.Fatal("expected points:", len(expected), ", not equal:", len(resp))
+	}
+
+	for i, stock := range resp {
+		if !stock.Date.Equal(expected[i]) {
+			t.Errorf("point %d time [%s] not equal expected [%s]", i, stock.Date.UTC(), expected[i])
+		}
+	}
+}